@@ -3,13 +3,24 @@ module github.com/mkvone/transaction-monitor
 go 1.21.1
 
 require (
-	github.com/go-yaml/yaml v2.1.0+incompatible
+	github.com/nats-io/nats.go v1.31.0
+	github.com/redis/go-redis/v9 v9.5.1
 	github.com/sacOO7/gowebsocket v0.0.0-20221109081133-70ac927be105
+	github.com/segmentio/kafka-go v0.4.47
+	golang.org/x/sync v0.5.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
 	github.com/gorilla/websocket v1.4.2 // indirect
+	github.com/klauspost/compress v1.17.2 // indirect
+	github.com/nats-io/nkeys v0.4.6 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
+	github.com/pierrec/lz4/v4 v4.1.15 // indirect
 	github.com/sacOO7/go-logger v0.0.0-20180719173527-9ac9add5a50d // indirect
+	golang.org/x/crypto v0.14.0 // indirect
+	golang.org/x/sys v0.13.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
-	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
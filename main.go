@@ -2,16 +2,23 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"log"
+	"os"
 
 	"github.com/mkvone/transaction-monitor/pkg"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(validate(os.Args[2:]))
+	}
 
 	// Define a flag for the configuration path
 	var configPath string
-	flag.StringVar(&configPath, "config-path", "./config.yml", "Path to configuration file")
+	var sinks string
+	flag.StringVar(&configPath, "config-path", "./config.yml", "Path to configuration file or directory")
+	flag.StringVar(&sinks, "sink", "", "Comma-separated local sinks to enable: stdout, syslog, file:<path>")
 	flag.Parse() // Parse the flags
 
 	// Load the configuration using the path provided in the command line argument
@@ -19,7 +26,33 @@ func main() {
 	if err != nil {
 		log.Fatalf("Error loading config: %v", err)
 	}
+	if err := pkg.EnableSinks(cfg, sinks); err != nil {
+		log.Fatalf("Error enabling sinks: %v", err)
+	}
 
 	// Run the application with the loaded configuration
 	pkg.Run(cfg)
 }
+
+// validate implements "transaction-monitor validate [--config-path path]": it prints
+// every problem of the config and exits non-zero if there are any.
+func validate(args []string) int {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	configPath := flags.String("config-path", "./config.yml", "Path to configuration file or directory")
+	flags.Parse(args)
+
+	problems, err := pkg.ValidateConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading config file: %v\n", err)
+		return 2
+	}
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if len(problems) > 0 {
+		fmt.Printf("%d problem(s) found\n", len(problems))
+		return 1
+	}
+	fmt.Printf("%s is valid\n", *configPath)
+	return 0
+}
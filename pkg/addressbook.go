@@ -0,0 +1,99 @@
+package pkg
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// addressLabels maps addresses to the names shown in alerts: wallet labels and the
+// address book from the config and its CSV file.
+type addressLabels struct {
+	mu     sync.RWMutex
+	labels map[string]string
+}
+
+var addressBook = &addressLabels{labels: make(map[string]string)}
+
+func (a *addressLabels) Set(labels map[string]string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.labels = labels
+}
+
+// Label returns the name of an address, or "" when it is unknown.
+func (a *addressLabels) Label(address string) string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.labels[address]
+}
+
+// Address returns the address with the given label (case-insensitive), or false when
+// no address or more than one carries it.
+func (a *addressLabels) Address(label string) (string, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	found := ""
+	for address, name := range a.labels {
+		if strings.EqualFold(name, label) {
+			if found != "" {
+				return "", false
+			}
+			found = address
+		}
+	}
+	return found, found != ""
+}
+
+// Display renders a known address as "Treasury (kava18zx…cql)" and returns others unchanged.
+func (a *addressLabels) Display(address string) string {
+	label := a.Label(address)
+	if label == "" {
+		return address
+	}
+	return fmt.Sprintf("%s (%s)", label, shortAddress(address))
+}
+
+// shortAddress keeps the bech32 prefix, three characters on each side and an ellipsis.
+func shortAddress(address string) string {
+	start := strings.LastIndex(address, "1") + 4
+	if start < 4 || start+3 >= len(address)-3 {
+		return address
+	}
+	return address[:start] + "…" + address[len(address)-3:]
+}
+
+// loadAddressBook reads "address,label" rows. A first row of "address,label" is a header.
+func loadAddressBook(path string, labels map[string]string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(record) < 2 {
+			line, _ := reader.FieldPos(0)
+			return fmt.Errorf("%s:%d: expected address,label", path, line)
+		}
+		address, label := strings.TrimSpace(record[0]), strings.TrimSpace(record[1])
+		if first && strings.EqualFold(address, "address") {
+			continue
+		}
+		labels[address] = label
+	}
+}
@@ -0,0 +1,114 @@
+package pkg
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// RunAdminAPI serves the admin HTTP API:
+//
+//	GET    /mutes                list mute windows
+//	POST   /mutes                add a mute window (MuteWindow as JSON)
+//	DELETE /mutes/{id}           remove a mute window added at runtime
+//	GET    /history?chain=&limit= recent alerts, including muted and filtered ones
+//	GET    /status               WebSocket connection state
+func RunAdminAPI(cfg *Config) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mutes", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, mutes.List())
+		case http.MethodPost:
+			var window MuteWindow
+			if err := json.NewDecoder(r.Body).Decode(&window); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if err := window.compile(); err != nil {
+				writeError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			added := mutes.Add(window)
+			log.Printf("Mute window %s added through the admin API", added.ID)
+			writeJSON(w, http.StatusCreated, added)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})
+	mux.HandleFunc("/mutes/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		id := strings.TrimPrefix(r.URL.Path, "/mutes/")
+		if !mutes.Remove(id) {
+			writeError(w, http.StatusNotFound, "no runtime mute window "+id)
+			return
+		}
+		log.Printf("Mute window %s removed through the admin API", id)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/history", func(w http.ResponseWriter, r *http.Request) {
+		chainName, ok := findChain(currentConfig(), r.URL.Query().Get("chain"))
+		if !ok {
+			writeError(w, http.StatusNotFound, "unknown chain")
+			return
+		}
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		if err != nil || limit <= 0 {
+			limit = historySize
+		}
+		writeJSON(w, http.StatusOK, history.Last(chainName, limit))
+	})
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, connections.Snapshot())
+	})
+
+	log.Printf("Serving admin API on %s", cfg.Admin.Listen)
+	if err := http.ListenAndServe(cfg.Admin.Listen, adminAuth(cfg.Admin.Token, mux)); err != nil {
+		log.Printf("Admin API stopped: %v", err)
+	}
+}
+
+// isLoopbackListen reports whether a listen address such as "127.0.0.1:8091" only
+// accepts connections from the host itself. ":8091" listens on every interface.
+func isLoopbackListen(listen string) bool {
+	host, _, err := net.SplitHostPort(listen)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// adminAuth requires "Authorization: Bearer <token>" when a token is configured.
+func adminAuth(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token != "" {
+			given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				writeError(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
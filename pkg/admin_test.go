@@ -0,0 +1,21 @@
+package pkg
+
+import "testing"
+
+func TestIsLoopbackListen(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1:8091": true,
+		"localhost:8091": true,
+		"[::1]:8091":     true,
+		":8091":          false,
+		"0.0.0.0:8091":   false,
+		"10.0.0.5:8091":  false,
+		"example.com:80": false,
+		"127.0.0.1":      false,
+	}
+	for listen, want := range tests {
+		if got := isLoopbackListen(listen); got != want {
+			t.Errorf("isLoopbackListen(%q) = %v, want %v", listen, got, want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type AuthzGrant struct {
+	Authorization AuthzAuthorization `json:"authorization"`
+	Expiration    *string            `json:"expiration,omitempty"`
+}
+
+// AuthzAuthorization holds the fields of the authorization types of the SDK.
+type AuthzAuthorization struct {
+	Type string `json:"@type"`
+	// GenericAuthorization
+	Msg string `json:"msg,omitempty"`
+	// SendAuthorization
+	SpendLimit []Amount `json:"spend_limit,omitempty"`
+	// StakeAuthorization
+	MaxTokens         *Amount `json:"max_tokens,omitempty"`
+	AuthorizationType string  `json:"authorization_type,omitempty"`
+	// A list of addresses for SendAuthorization, {"address": [...]} for StakeAuthorization.
+	AllowList json.RawMessage `json:"allow_list,omitempty"`
+	DenyList  json.RawMessage `json:"deny_list,omitempty"`
+}
+
+// addresses reads an allow or deny list in either format.
+func (a AuthzAuthorization) addresses(list json.RawMessage) []string {
+	var addresses []string
+	if json.Unmarshal(list, &addresses) == nil {
+		return addresses
+	}
+	var validators struct {
+		Address []string `json:"address"`
+	}
+	json.Unmarshal(list, &validators)
+	return validators.Address
+}
+
+// populateAuthzDetails adds what a MsgGrant grants or a MsgRevoke revokes. The messages
+// of a MsgExec are decoded as nested MessageDetails.
+func populateAuthzDetails(details *MessageDetail, message Message, chain ChainConfig) {
+	switch message.Type {
+	case "/cosmos.authz.v1beta1.MsgGrant":
+		if message.Grant == nil {
+			return
+		}
+		authorization := describeAuthorization(message.Grant.Authorization, chain)
+		appendIfNotNil(&details.Details, "Authorization", &authorization)
+		for _, address := range message.Grant.Authorization.addresses(message.Grant.Authorization.AllowList) {
+			allowed := addressBook.Display(address)
+			appendIfNotNil(&details.Details, "Allowed", &allowed)
+		}
+		for _, address := range message.Grant.Authorization.addresses(message.Grant.Authorization.DenyList) {
+			denied := addressBook.Display(address)
+			appendIfNotNil(&details.Details, "Denied", &denied)
+		}
+		expiration := "never"
+		if message.Grant.Expiration != nil {
+			expiration = *message.Grant.Expiration
+			if parsed, err := time.Parse(time.RFC3339, expiration); err == nil {
+				expiration = parsed.UTC().Format("2006-01-02 15:04 MST")
+			}
+		}
+		appendIfNotNil(&details.Details, "Expiration", &expiration)
+	case "/cosmos.authz.v1beta1.MsgRevoke":
+		appendIfNotNil(&details.Details, "Message Type", message.MsgTypeURL)
+	case "/cosmos.authz.v1beta1.MsgExec":
+		if len(message.Msgs) > 0 {
+			var actions []string
+			for _, inner := range message.Msgs {
+				actions = append(actions, getMessageAction(inner.Type))
+			}
+			executed := strings.Join(actions, ", ")
+			appendIfNotNil(&details.Details, "Executes", &executed)
+		}
+	}
+}
+
+// describeAuthorization renders a grant, e.g. "Send up to 10 atom" or
+// "Generic: /cosmos.gov.v1beta1.MsgVote".
+func describeAuthorization(authorization AuthzAuthorization, chain ChainConfig) string {
+	switch authorization.Type {
+	case "/cosmos.authz.v1beta1.GenericAuthorization":
+		return "Generic: " + authorization.Msg
+	case "/cosmos.bank.v1beta1.SendAuthorization":
+		if coins := chain.displayCoins(authorization.SpendLimit); len(coins) > 0 {
+			return "Send up to " + formatCoins(coins)
+		}
+		return "Send"
+	case "/cosmos.staking.v1beta1.StakeAuthorization":
+		action := map[string]string{
+			"AUTHORIZATION_TYPE_DELEGATE":                    "Delegate",
+			"AUTHORIZATION_TYPE_UNDELEGATE":                  "Undelegate",
+			"AUTHORIZATION_TYPE_REDELEGATE":                  "Redelegate",
+			"AUTHORIZATION_TYPE_CANCEL_UNBONDING_DELEGATION": "Cancel Unbonding",
+		}[authorization.AuthorizationType]
+		if action == "" {
+			action = authorization.AuthorizationType
+		}
+		if authorization.MaxTokens != nil {
+			return fmt.Sprintf("%s up to %s", action, formatCoins(chain.displayCoins([]Amount{*authorization.MaxTokens})))
+		}
+		return action
+	default:
+		return authorization.Type
+	}
+}
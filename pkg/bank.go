@@ -0,0 +1,59 @@
+package pkg
+
+// BankIO is an input or output of a MsgMultiSend.
+type BankIO struct {
+	Address string   `json:"address"`
+	Coins   []Amount `json:"coins"`
+}
+
+// populateBankDetails adds every input and output of a MsgMultiSend as
+// "address: amount". When the monitored wallet is among them, only its own coins are
+// the message's amounts, sent if it is an input and received otherwise, and the
+// addresses on the other side are its counterparties. Without a wallet, as for /tx,
+// the inputs, which the outputs add up to, are its amounts.
+func (d messageDecoder) populateBankDetails(details *MessageDetail, message Message) {
+	if message.Type != "/cosmos.bank.v1beta1.MsgMultiSend" {
+		return
+	}
+	for _, io := range []struct {
+		key     string
+		entries []BankIO
+	}{{"Input", message.Inputs}, {"Output", message.Outputs}} {
+		for _, entry := range io.entries {
+			text := addressBook.Display(entry.Address) + ": " + formatCoins(d.prices.value(d.chain.displayCoins(entry.Coins)))
+			appendIfNotNil(&details.Details, io.key, &text)
+		}
+	}
+
+	own, others := message.Inputs, message.Outputs
+	if !hasBankAddress(own, d.wallet) && hasBankAddress(others, d.wallet) {
+		own, others = others, own
+	}
+	if !hasBankAddress(own, d.wallet) {
+		for _, entry := range message.Inputs {
+			details.Addresses = append(details.Addresses, entry.Address)
+			details.Amounts = append(details.Amounts, d.prices.value(d.chain.displayCoins(entry.Coins))...)
+		}
+		for _, entry := range message.Outputs {
+			details.Addresses = append(details.Addresses, entry.Address)
+		}
+		return
+	}
+	for _, entry := range own {
+		if entry.Address == d.wallet {
+			details.Amounts = append(details.Amounts, d.prices.value(d.chain.displayCoins(entry.Coins))...)
+		}
+	}
+	for _, entry := range others {
+		details.Addresses = append(details.Addresses, entry.Address)
+	}
+}
+
+func hasBankAddress(entries []BankIO, address string) bool {
+	for _, entry := range entries {
+		if address != "" && entry.Address == address {
+			return true
+		}
+	}
+	return false
+}
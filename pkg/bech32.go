@@ -0,0 +1,69 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// decodeBech32 checks a bech32 address (BIP-173) and returns its prefix and 5-bit data.
+func decodeBech32(address string) (string, []byte, error) {
+	if len(address) < 8 || len(address) > 128 {
+		return "", nil, fmt.Errorf("invalid length %d", len(address))
+	}
+	if strings.ToLower(address) != address && strings.ToUpper(address) != address {
+		return "", nil, fmt.Errorf("mixed case")
+	}
+	address = strings.ToLower(address)
+
+	sep := strings.LastIndex(address, "1")
+	if sep < 1 || sep+7 > len(address) {
+		return "", nil, fmt.Errorf("missing separator")
+	}
+	hrp := address[:sep]
+	for _, c := range hrp {
+		if c < 33 || c > 126 {
+			return "", nil, fmt.Errorf("invalid prefix character %q", c)
+		}
+	}
+	data := make([]byte, 0, len(address)-sep-1)
+	for _, c := range address[sep+1:] {
+		i := strings.IndexRune(bech32Charset, c)
+		if i < 0 {
+			return "", nil, fmt.Errorf("invalid character %q", c)
+		}
+		data = append(data, byte(i))
+	}
+	if bech32Polymod(append(bech32ExpandPrefix(hrp), data...)) != 1 {
+		return "", nil, fmt.Errorf("invalid checksum")
+	}
+	return hrp, data[:len(data)-6], nil
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32ExpandPrefix(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for _, c := range hrp {
+		expanded = append(expanded, byte(c>>5))
+	}
+	expanded = append(expanded, 0)
+	for _, c := range hrp {
+		expanded = append(expanded, byte(c&31))
+	}
+	return expanded
+}
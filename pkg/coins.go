@@ -0,0 +1,53 @@
+package pkg
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// coinPattern is a coin as the SDK prints it: an integer or decimal amount followed by
+// a denom such as "uatom", "ibc/27394FB0…" or "factory/osmo1…/ufoo".
+var coinPattern = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([a-zA-Z][a-zA-Z0-9/:._-]{2,127})$`)
+
+// parseCoins parses a comma-separated list of coins like "100uatom,5ibc/27394FB0…",
+// as found in event attributes, in the format of sdk.Coins and sdk.DecCoins.
+func parseCoins(str string) ([]Amount, error) {
+	str = strings.TrimSpace(str)
+	if str == "" {
+		return nil, nil
+	}
+	var coins []Amount
+	for _, coin := range strings.Split(str, ",") {
+		match := coinPattern.FindStringSubmatch(strings.TrimSpace(coin))
+		if match == nil {
+			return nil, fmt.Errorf("invalid coin %q", coin)
+		}
+		coins = append(coins, Amount{Amount: match[1], Denom: match[2]})
+	}
+	return coins, nil
+}
+
+// displayCoins converts coins of base denoms to display units, leaving out zero amounts.
+func (c ChainConfig) displayCoins(amounts []Amount) []Coin {
+	var coins []Coin
+	for _, amount := range amounts {
+		if coin := c.displayCoin(amount); !coin.Amount.IsZero() {
+			coins = append(coins, coin)
+		}
+	}
+	return coins
+}
+
+// formatCoins renders coins as "1.5 atom ($15.30), 0.2 osmo".
+func formatCoins(coins []Coin) string {
+	var parts []string
+	for _, coin := range coins {
+		part := fmt.Sprintf("%s %s", coin.Amount, coin.Denom)
+		if coin.Value != nil {
+			part += fmt.Sprintf(" (%s)", coin.Value)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
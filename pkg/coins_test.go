@@ -0,0 +1,63 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+)
+
+const atomVoucher = "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2"
+
+func TestParseCoins(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []Amount
+	}{
+		{"", nil},
+		{"100uatom", []Amount{{Denom: "uatom", Amount: "100"}}},
+		{"100uatom,5" + atomVoucher, []Amount{{Denom: "uatom", Amount: "100"}, {Denom: atomVoucher, Amount: "5"}}},
+		{"1000factory/osmo1abc/ufoo", []Amount{{Denom: "factory/osmo1abc/ufoo", Amount: "1000"}}},
+		{"1500000000000000000aevmos", []Amount{{Denom: "aevmos", Amount: "1500000000000000000"}}},
+		{"0.250000000000000000uatom", []Amount{{Denom: "uatom", Amount: "0.250000000000000000"}}}, // sdk.DecCoins
+		{" 100uatom , 200 uosmo ", []Amount{{Denom: "uatom", Amount: "100"}, {Denom: "uosmo", Amount: "200"}}},
+		{"7cw20:juno1contract", []Amount{{Denom: "cw20:juno1contract", Amount: "7"}}},
+	}
+	for _, tt := range tests {
+		got, err := parseCoins(tt.in)
+		if err != nil {
+			t.Errorf("parseCoins(%q): %v", tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseCoins(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	for _, in := range []string{"100", "uatom", "-5uatom", "100u", "100uatom,", "1.uatom"} {
+		if got, err := parseCoins(in); err == nil {
+			t.Errorf("parseCoins(%q) = %v, want an error", in, got)
+		}
+	}
+}
+
+func TestDisplayCoins(t *testing.T) {
+	chain := ChainConfig{Denoms: map[string]DenomMetadata{"aevmos": {Display: "evmos", Exponent: 18}}}
+	tests := []struct {
+		in, want string
+	}{
+		{"1500000uatom", "1.5 atom"},
+		{"1uatom", "0.000001 atom"},
+		{"1500000000000000000aevmos", "1.5 evmos"},
+		{"1aevmos", "0.000000000000000001 evmos"},
+		{"1000factory/osmo1abc/ufoo", "1000 factory/osmo1abc/ufoo"},
+		{"5" + atomVoucher, "5 " + atomVoucher},
+		{"0uatom,3uosmo", "0.000003 osmo"},
+	}
+	for _, tt := range tests {
+		amounts, err := parseCoins(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := formatCoins(chain.displayCoins(amounts)); got != tt.want {
+			t.Errorf("displayCoins(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
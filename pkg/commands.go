@@ -0,0 +1,235 @@
+package pkg
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// chatMarkup describes how a chat platform formats bot replies.
+type chatMarkup struct {
+	bold        string
+	renderAlert func(alertData AlertData) string
+}
+
+func (m chatMarkup) b(text string) string {
+	return m.bold + text + m.bold
+}
+
+// runCommand executes a bot command (without its leading slash) and returns the reply.
+func runCommand(cfg *Config, m chatMarkup, command string, args []string) string {
+	switch command {
+	case "status":
+		return commandStatus(m)
+	case "wallets":
+		return commandWallets(cfg, m)
+	case "last":
+		if len(args) < 1 {
+			return "Usage: `/last <chain> [count]`"
+		}
+		count := 5
+		if len(args) > 1 {
+			if n, err := strconv.Atoi(args[1]); err == nil && n > 0 {
+				count = n
+			}
+		}
+		return commandLast(cfg, m, args[0], count)
+	case "mute":
+		if len(args) < 2 {
+			return "Usage: `/mute <wallet> <duration>`, e.g. `/mute kava1... 2h`"
+		}
+		walletAddress, reply := findWallet(cfg, args[0])
+		if reply != "" {
+			return reply
+		}
+		duration, err := time.ParseDuration(args[1])
+		if err != nil || duration <= 0 {
+			return fmt.Sprintf("Invalid duration `%s`", args[1])
+		}
+		until := time.Now().Add(duration)
+		mutes.Mute(walletAddress, until, "bot command")
+		return fmt.Sprintf("Muted `%s` until `%s`", walletAddress, until.UTC().Format(time.RFC3339))
+	case "unmute":
+		if len(args) < 1 {
+			return "Usage: `/unmute <wallet>`"
+		}
+		walletAddress, reply := findWallet(cfg, args[0])
+		if reply != "" {
+			walletAddress = args[0] // still lift mutes of wallets no longer monitored
+		}
+		if !mutes.Unmute(walletAddress) {
+			return fmt.Sprintf("`%s` is not muted", args[0])
+		}
+		return fmt.Sprintf("Unmuted `%s`", walletAddress)
+	case "tx":
+		if len(args) < 2 {
+			return "Usage: `/tx <chain> <hash>`"
+		}
+		alertData, reply := commandTx(cfg, args[0], args[1])
+		if reply != "" {
+			return reply
+		}
+		return m.renderAlert(alertData)
+	default:
+		return m.b("Commands") + "\n" +
+			"/status - connection state per chain\n" +
+			"/wallets - monitored wallets\n" +
+			"/last <chain> [count] - recent alerts\n" +
+			"/mute <wallet> <duration> - silence a wallet\n" +
+			"/unmute <wallet> - lift a mute\n" +
+			"/tx <chain> <hash> - render any transaction"
+	}
+}
+
+func commandStatus(m chatMarkup) string {
+	snapshot := connections.Snapshot()
+	if len(snapshot) == 0 {
+		return "No subscriptions yet"
+	}
+
+	messageText := m.b("Connection status") + "\n"
+	for _, chainName := range sortedKeys(snapshot) {
+		wallets := snapshot[chainName]
+		connected := 0
+		for _, state := range wallets {
+			if state.Connected {
+				connected++
+			}
+		}
+		messageText += fmt.Sprintf("\n%s %d/%d connected\n", m.b(chainName), connected, len(wallets))
+		for _, walletAddress := range sortedKeys(wallets) {
+			state := wallets[walletAddress]
+			status := "🟢"
+			if !state.Connected {
+				status = "🔴"
+			}
+			if label := addressBook.Label(walletAddress); label != "" {
+				status += " " + label
+			}
+			messageText += fmt.Sprintf("%s `%s` since %s\n", status, walletAddress, state.Since.UTC().Format(time.RFC3339))
+			if state.LastError != "" {
+				messageText += fmt.Sprintf("   `%s`\n", state.LastError)
+			}
+		}
+	}
+	return messageText
+}
+
+func commandWallets(cfg *Config, m chatMarkup) string {
+	messageText := m.b("Monitored wallets") + "\n"
+	for _, chainName := range sortedKeys(cfg.Chains) {
+		messageText += fmt.Sprintf("\n%s\n", m.b(chainName))
+		for _, walletInfo := range cfg.Chains[chainName].WalletInfo {
+			if walletInfo.Label != "" {
+				messageText += walletInfo.Label + " "
+			}
+			messageText += fmt.Sprintf("`%s`", walletInfo.WalletAddress)
+			if window, until := mutes.Match(chainName, walletInfo.WalletAddress, ""); window != nil {
+				messageText += fmt.Sprintf(" (muted until %s)", until.UTC().Format(time.RFC3339))
+			}
+			messageText += "\n"
+		}
+	}
+	return messageText
+}
+
+func commandLast(cfg *Config, m chatMarkup, chain string, count int) string {
+	chainName, ok := findChain(cfg, chain)
+	if !ok {
+		return fmt.Sprintf("Unknown chain `%s`", chain)
+	}
+	records := history.Last(chainName, count)
+	if len(records) == 0 {
+		return fmt.Sprintf("No alerts for %s yet", chainName)
+	}
+
+	messageText := m.b(fmt.Sprintf("Last %d alerts on %s", len(records), chainName)) + "\n"
+	for _, record := range records {
+		var actions []string
+		for _, detail := range record.Alert.MessageDetails {
+			actions = append(actions, detail.Action)
+		}
+		result := "✅"
+		if record.Alert.Error != "" {
+			result = "❌"
+		}
+		messageText += fmt.Sprintf("\n%s [%s](%s) %s\n%s, %s\n", result, shortHash(record.Alert.TxHash),
+			record.Alert.TxURL(), record.Status,
+			strings.Join(actions, ", "), record.ReceivedAt.UTC().Format(time.RFC3339))
+	}
+	return messageText
+}
+
+// findChain resolves a chain name case-insensitively.
+func findChain(cfg *Config, name string) (string, bool) {
+	for chainName := range cfg.Chains {
+		if strings.EqualFold(chainName, name) {
+			return chainName, true
+		}
+	}
+	return "", false
+}
+
+// findWallet resolves a monitored wallet address or an address book label to an
+// address. Otherwise it returns the reply explaining why the wallet was rejected.
+func findWallet(cfg *Config, name string) (string, string) {
+	if address, ok := addressBook.Address(name); ok {
+		return address, ""
+	}
+	if _, _, err := decodeBech32(name); err != nil {
+		return "", fmt.Sprintf("Invalid wallet `%s`: %v", name, err)
+	}
+	for _, chain := range cfg.Chains {
+		for _, walletInfo := range chain.WalletInfo {
+			if walletInfo.WalletAddress == name {
+				return name, ""
+			}
+		}
+	}
+	return "", fmt.Sprintf("`%s` is not a monitored wallet", name)
+}
+
+// commandTx fetches any transaction for /tx. On failure it returns the reply to send
+// instead.
+func commandTx(cfg *Config, chain string, hash string) (AlertData, string) {
+	chainName, ok := findChain(cfg, chain)
+	if !ok {
+		return AlertData{}, fmt.Sprintf("Unknown chain `%s`", chain)
+	}
+	if !isTxHash(hash) {
+		return AlertData{}, "Invalid transaction hash, expected 64 hexadecimal characters"
+	}
+	alertData, err := buildAlertData(cfg, chainName, "", hash)
+	if err != nil {
+		return AlertData{}, fmt.Sprintf("Error fetching transaction: `%v`", err)
+	}
+	return alertData, ""
+}
+
+// isTxHash reports whether s is a transaction hash: 64 hexadecimal characters.
+func isTxHash(s string) bool {
+	if len(s) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+func shortHash(hash string) string {
+	if len(hash) <= 12 {
+		return hash
+	}
+	return hash[:6] + "…" + hash[len(hash)-6:]
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,62 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestIsTxHash(t *testing.T) {
+	hash := strings.Repeat("AB12", 16)
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{hash, true},
+		{strings.ToLower(hash), true},
+		{hash[:63], false},
+		{hash + "00", false},
+		{hash[:62] + "zz", false},
+		{"../../bank/v1beta1/balances/" + hash[:36], false},
+		{hash[:32] + "/?a=" + hash[:28], false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isTxHash(tt.in); got != tt.want {
+			t.Errorf("isTxHash(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFindWallet(t *testing.T) {
+	var chain ChainConfig
+	err := yaml.Unmarshal([]byte("wallet_Info:\n  - wallet_address: kava18zxhj6f8lm988mfzzvmrmlp47yys0fmcjfpcql\n"), &chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{Chains: map[string]ChainConfig{"Kava": chain}}
+	addressBook.Set(map[string]string{
+		"kava18zxhj6f8lm988mfzzvmrmlp47yys0fmcjfpcql": "Treasury",
+		"kava1a": "Ops", "kava1b": "Ops",
+	})
+	defer addressBook.Set(make(map[string]string))
+
+	tests := []struct {
+		in, want string
+		ok       bool
+	}{
+		{"kava18zxhj6f8lm988mfzzvmrmlp47yys0fmcjfpcql", "kava18zxhj6f8lm988mfzzvmrmlp47yys0fmcjfpcql", true},
+		{"treasury", "kava18zxhj6f8lm988mfzzvmrmlp47yys0fmcjfpcql", true},
+		{"Ops", "", false}, // ambiguous label
+		{"kava1", "", false},
+		{"kava18zxhj6f8lm988mfzzvmrmlp47yys0fmcjfpcqm", "", false},   // bad checksum
+		{"cosmos1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu", "", false}, // not monitored
+	}
+	for _, tt := range tests {
+		got, reply := findWallet(cfg, tt.in)
+		if got != tt.want || (reply == "") != tt.ok {
+			t.Errorf("findWallet(%q) = %q, %q; want %q, ok %v", tt.in, got, reply, tt.want, tt.ok)
+		}
+	}
+}
@@ -1,55 +1,300 @@
 package pkg
 
 import (
+	"fmt"
 	"log"
-	"os"
+	"reflect"
+	"sort"
+	"strings"
 
-	"github.com/go-yaml/yaml"
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
 	Alerting Alerting               `yaml:"alerting"`
 	Chains   map[string]ChainConfig `yaml:"chains"`
+	Admin    struct {
+		Listen string `yaml:"listen"`
+		Token  string `yaml:"token"`
+	} `yaml:"admin"`
+	AddressBook struct {
+		File      string            `yaml:"file"`      // CSV of address,label
+		Addresses map[string]string `yaml:"addresses"` // address -> label
+	} `yaml:"address_book"`
+	ChainRegistry string `yaml:"chain_registry"` // checkout of github.com/cosmos/chain-registry
+	CacheDir      string `yaml:"cache_dir"`      // lookups kept across restarts, such as IBC denom traces
+	Prices        Prices `yaml:"prices"`
+
+	labels map[string]string
+	path   string // file the config was loaded from, for reloads
+	sinks  string // --sink flag, applied again on reload
 }
 type Alerting struct {
 	Slack struct {
 		Enable     bool   `yaml:"enable"`
 		WebhookURL string `yaml:"webhook_url"`
+		Bot        struct {
+			Enable   bool     `yaml:"enable"`
+			BotToken string   `yaml:"bot_token"`
+			Channels []string `yaml:"channels"`
+			FollowUp string   `yaml:"follow_up"` // "thread" or "update"
+		} `yaml:"bot"`
 	} `yaml:"slack"`
 	Telegram struct {
 		Enable   bool   `yaml:"enable"`
 		BotToken string `yaml:"bot_token"`
 		ChatID   string `yaml:"chat_id"`
+		Commands struct {
+			Enable            bool     `yaml:"enable"`
+			AuthorizedChatIDs []string `yaml:"authorized_chat_ids"`
+		} `yaml:"commands"`
 	} `yaml:"telegram"`
 	Discord struct {
 		Enable     bool   `yaml:"enable"`
 		WebhookURL string `yaml:"webhook_url"`
+		Bot        struct {
+			Enable            bool     `yaml:"enable"`
+			BotToken          string   `yaml:"bot_token"`
+			ApplicationID     string   `yaml:"application_id"`
+			PublicKey         string   `yaml:"public_key"`
+			GuildID           string   `yaml:"guild_id"`
+			ChannelID         string   `yaml:"channel_id"`
+			ThreadPerWallet   bool     `yaml:"thread_per_wallet"`
+			Listen            string   `yaml:"listen"`
+			AuthorizedUserIDs []string `yaml:"authorized_user_ids"`
+		} `yaml:"bot"`
 	} `yaml:"discord"`
+	Matrix struct {
+		Enable      bool   `yaml:"enable"`
+		Homeserver  string `yaml:"homeserver"`
+		AccessToken string `yaml:"access_token"`
+		RoomID      string `yaml:"room_id"`
+	} `yaml:"matrix"`
+	Mattermost struct {
+		Enable     bool   `yaml:"enable"`
+		WebhookURL string `yaml:"webhook_url"`
+		Channel    string `yaml:"channel"`
+	} `yaml:"mattermost"`
+	Teams struct {
+		Enable     bool   `yaml:"enable"`
+		WebhookURL string `yaml:"webhook_url"`
+	} `yaml:"teams"`
+	Nats struct {
+		Enable    bool   `yaml:"enable"`
+		URL       string `yaml:"url"`
+		Subject   string `yaml:"subject"`
+		JetStream bool   `yaml:"jetstream"`
+		QueueDir  string `yaml:"queue_dir"` // keeps queued events across restarts
+	} `yaml:"nats"`
+	Kafka struct {
+		Enable   bool     `yaml:"enable"`
+		Brokers  []string `yaml:"brokers"`
+		Topic    string   `yaml:"topic"`
+		QueueDir string   `yaml:"queue_dir"` // keeps queued events across restarts
+	} `yaml:"kafka"`
+	Redis struct {
+		Enable   bool   `yaml:"enable"`
+		Addr     string `yaml:"addr"`
+		Password string `yaml:"password"`
+		DB       int    `yaml:"db"`
+		Stream   string `yaml:"stream"`
+		MaxLen   int64  `yaml:"max_len"`
+		QueueDir string `yaml:"queue_dir"` // keeps queued events across restarts
+	} `yaml:"redis"`
+	File struct {
+		Enable     bool   `yaml:"enable"`
+		Path       string `yaml:"path"`
+		MaxSizeMB  int    `yaml:"max_size_mb"`
+		MaxBackups int    `yaml:"max_backups"`
+	} `yaml:"file"`
+	Stdout struct {
+		Enable bool `yaml:"enable"`
+	} `yaml:"stdout"`
+	Syslog struct {
+		Enable bool   `yaml:"enable"`
+		Tag    string `yaml:"tag"`
+	} `yaml:"syslog"`
+	Routes     []Route              `yaml:"routes"`
+	RateLimits map[string]RateLimit `yaml:"rate_limits"` // keyed by notifier, like routes
+	Mutes      []MuteWindow         `yaml:"mutes"`
 }
 type ChainConfig struct {
-	RPC        string `yaml:"rpc"`
-	API        string `yaml:"api"`
-	GRPC       string `yaml:"grpc"`
-	Explorer   string `yaml:"explorerURL"`
+	Registry   string                    `yaml:"registry"` // chain-registry name, e.g. "kava"
+	RPC        string                    `yaml:"rpc"`
+	API        string                    `yaml:"api"`
+	GRPC       string                    `yaml:"grpc"`
+	Explorer   string                    `yaml:"explorerURL"` // prefix of tx links, or a template with ${txHash}
+	Prefix     string                    `yaml:"bech32_prefix"`
+	Denoms     map[string]DenomMetadata  `yaml:"denoms"`    // keyed by base denom
+	Contracts  map[string]ContractConfig `yaml:"contracts"` // keyed by contract address
 	WalletInfo []struct {
-		WalletAddress string `yaml:"wallet_address"`
+		WalletAddress string  `yaml:"wallet_address"`
+		Label         string  `yaml:"label"`
+		Filter        *Filter `yaml:"filter"`
 	} `yaml:"wallet_Info"`
+
+	registryDenoms map[string]DenomMetadata
+	registryPath   string
 }
 
 func LoadConfig(path string) (*Config, error) {
-
-	data, err := os.ReadFile(path)
+	config, problems, err := parseConfig(path)
 	if err != nil {
 		log.Printf("Error reading config file from %s: %v", path, err)
 		return nil, err
 	}
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			log.Print(problem)
+		}
+		return nil, fmt.Errorf("%d problem(s) in %s, see above or run the validate command", len(problems), path)
+	}
+	config.path = path
+	return config, nil
+}
 
-	var config Config
-	err = yaml.Unmarshal(data, &config)
+// ValidateConfig reports every problem of a config file; err is only set when the
+// file cannot be read.
+func ValidateConfig(path string) ([]ConfigProblem, error) {
+	_, problems, err := parseConfig(path)
+	return problems, err
+}
+
+func parseConfig(path string) (*Config, []ConfigProblem, error) {
+	v := &validator{files: make(map[*yaml.Node]string)}
+	files, err := v.readConfigFiles(path)
 	if err != nil {
-		log.Printf("Error parsing config file: %v", err)
-		return nil, err
+		return nil, nil, err
+	}
+
+	var config Config
+	configType := reflect.TypeOf(config)
+	// Type errors are found per file, where their line numbers are unambiguous.
+	reported := make(map[string]bool)
+	for _, file := range files {
+		v.interpolateEnv(file.root)
+		var fileConfig Config
+		for _, problem := range decodeProblems(file.root.Decode(&fileConfig)) {
+			reported[problem.String()] = true
+			problem.File = file.path
+			v.problems = append(v.problems, problem)
+		}
+	}
+
+	root := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{v.mergeConfigFiles(files)}}
+	v.root = root
+	v.resolveSecretFiles(root, configType, nil)
+	v.applyEnvOverrides(root.Content[0], configType, EnvPrefix, configEnviron())
+	v.checkFields(root, configType, nil)
+	for _, problem := range decodeProblems(root.Decode(&config)) {
+		if !reported[problem.String()] {
+			v.problems = append(v.problems, problem)
+		}
 	}
+	config.compile(v)
+	config.validate(v)
 
-	return &config, nil
+	sort.SliceStable(v.problems, func(i, j int) bool {
+		if v.problems[i].File != v.problems[j].File {
+			return v.problems[i].File < v.problems[j].File
+		}
+		return v.problems[i].Line < v.problems[j].Line
+	})
+	return &config, v.problems, nil
+}
+
+// decodeProblems lists the errors of decoding YAML into the config.
+func decodeProblems(err error) []ConfigProblem {
+	if err == nil {
+		return nil
+	}
+	if typeErr, ok := err.(*yaml.TypeError); ok {
+		var problems []ConfigProblem
+		for _, message := range typeErr.Errors {
+			problems = append(problems, yamlProblem(message))
+		}
+		return problems
+	}
+	return []ConfigProblem{yamlProblem(err.Error())}
+}
+
+// yamlProblem turns a "line N: message" error of the YAML decoder into a ConfigProblem.
+func yamlProblem(message string) ConfigProblem {
+	message = strings.TrimPrefix(message, "yaml: ")
+	var line int
+	if n, _ := fmt.Sscanf(message, "line %d:", &line); n == 1 {
+		message = strings.TrimSpace(message[strings.Index(message, ":")+1:])
+	}
+	return ConfigProblem{Line: line, Message: message}
+}
+
+func (c *Config) compile(v *validator) {
+	for i, route := range c.Alerting.Routes {
+		if err := route.Filter.compile(); err != nil {
+			v.addf(at("alerting", "routes", i, "filter"), "%v", err)
+		}
+		if err := route.Digest.compile(); err != nil {
+			v.addf(at("alerting", "routes", i, "digest"), "%v", err)
+		}
+	}
+	for i := range c.Alerting.Mutes {
+		// A window without a start would open again on every reload.
+		if window := c.Alerting.Mutes[i]; window.Cron == "" && window.Start == "" && window.End == "" && window.Duration != "" {
+			v.addf(at("alerting", "mutes", i, "start"), "required with duration unless cron is set")
+			continue
+		}
+		if err := c.Alerting.Mutes[i].compile(); err != nil {
+			v.addf(at("alerting", "mutes", i), "%v", err)
+		}
+	}
+	for _, name := range sortedKeys(c.Alerting.RateLimits) {
+		if err := c.Alerting.RateLimits[name].validate(); err != nil {
+			v.addf(at("alerting", "rate_limits", name), "%v", err)
+		}
+	}
+	if err := c.Prices.compile(); err != nil {
+		v.addf(at("prices"), "%v", err)
+	}
+	c.labels = make(map[string]string)
+	if c.AddressBook.File != "" {
+		if err := loadAddressBook(c.AddressBook.File, c.labels); err != nil {
+			v.addf(at("address_book", "file"), "%v", err)
+		}
+	}
+	for address, label := range c.AddressBook.Addresses {
+		c.labels[address] = label
+	}
+	for _, chainName := range sortedKeys(c.Chains) {
+		chain := c.Chains[chainName]
+		chain.registryPath = c.ChainRegistry
+		if chain.Registry != "" {
+			if err := chain.resolveRegistry(c.ChainRegistry); err != nil {
+				v.addf(at("chains", chainName, "registry"), "%v", err)
+			}
+		}
+		c.Chains[chainName] = chain
+		for _, address := range sortedKeys(chain.Contracts) {
+			if label := chain.Contracts[address].Label; label != "" {
+				c.labels[address] = label
+			}
+		}
+		for i, walletInfo := range c.Chains[chainName].WalletInfo {
+			if err := walletInfo.Filter.compile(); err != nil {
+				v.addf(at("chains", chainName, "wallet_Info", i, "filter"), "%v", err)
+			}
+			if walletInfo.Label != "" {
+				c.labels[walletInfo.WalletAddress] = walletInfo.Label
+			}
+		}
+	}
+}
+
+// WalletFilter returns the filter configured on a wallet, if any.
+func (c *Config) WalletFilter(chainName string, walletAddress string) *Filter {
+	for _, walletInfo := range c.Chains[chainName].WalletInfo {
+		if walletInfo.WalletAddress == walletAddress {
+			return walletInfo.Filter
+		}
+	}
+	return nil
 }
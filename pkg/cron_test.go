@@ -0,0 +1,71 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronMatches(t *testing.T) {
+	tests := []struct {
+		expr string
+		at   string
+		want bool
+	}{
+		{"0 2 * * SUN", "2024-05-05T02:00:00Z", true},
+		{"0 2 * * SUN", "2024-05-05T02:01:00Z", false},
+		{"0 2 * * MON-SUN", "2024-05-05T02:00:00Z", true},
+		{"0 2 * * MON-SUN", "2024-05-06T02:00:00Z", true},
+		{"0 2 * * SAT-SUN", "2024-05-01T02:00:00Z", false},
+		{"0 2 * * 7", "2024-05-05T02:00:00Z", true},
+		// */2 is unrestricted for combining, so both fields must match.
+		{"0 0 */2 * MON", "2024-05-06T00:00:00Z", false},
+		{"0 0 */2 * MON", "2024-05-01T00:00:00Z", false},
+		{"0 0 */2 * MON", "2024-05-13T00:00:00Z", true},
+		// Two restricted fields are OR-ed.
+		{"0 0 1 * MON", "2024-05-01T00:00:00Z", true},
+		{"0 0 1 * MON", "2024-05-06T00:00:00Z", true},
+		{"0 0 1 * MON", "2024-05-07T00:00:00Z", false},
+	}
+	for _, tt := range tests {
+		schedule, err := parseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("parseCron(%q): %v", tt.expr, err)
+		}
+		at, _ := time.Parse(time.RFC3339, tt.at)
+		if got := schedule.Matches(at); got != tt.want {
+			t.Errorf("%q.Matches(%s) = %v, want %v", tt.expr, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestCronLastWithin(t *testing.T) {
+	tests := []struct {
+		expr   string
+		at     string
+		window time.Duration
+		want   string // "" when the schedule did not fire
+	}{
+		{"0 2 * * SUN", "2024-05-05T03:59:30Z", 2 * time.Hour, "2024-05-05T02:00:00Z"},
+		{"0 2 * * SUN", "2024-05-05T04:00:00Z", 2 * time.Hour, ""},
+		{"0 2 * * SUN", "2024-05-05T02:00:00Z", time.Minute, "2024-05-05T02:00:00Z"},
+		{"*/15 * * * *", "2024-05-05T10:14:00Z", time.Hour, "2024-05-05T10:00:00Z"},
+		{"30 23 * * *", "2024-05-05T10:00:00Z", 24 * time.Hour, "2024-05-04T23:30:00Z"},
+		{"0 0 1 1 *", "2024-05-05T10:00:00Z", 24 * time.Hour, ""},
+		{"0 0 1 1 *", "2024-05-05T10:00:00Z", 366 * 24 * time.Hour, "2024-01-01T00:00:00Z"},
+	}
+	for _, tt := range tests {
+		schedule, err := parseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("parseCron(%q): %v", tt.expr, err)
+		}
+		at, _ := time.Parse(time.RFC3339, tt.at)
+		fire, ok := schedule.LastWithin(at, tt.window)
+		got := ""
+		if ok {
+			got = fire.Format(time.RFC3339)
+		}
+		if got != tt.want {
+			t.Errorf("%q.LastWithin(%s, %s) = %q, want %q", tt.expr, tt.at, tt.window, got, tt.want)
+		}
+	}
+}
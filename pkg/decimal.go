@@ -0,0 +1,164 @@
+package pkg
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Decimal is an exact decimal number, unscaled × 10^-scale, so that amounts of
+// 18-decimal denoms and large balances keep every digit. The zero value is 0.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int
+}
+
+// NewDecimal returns unscaled × 10^-scale, e.g. an amount of base units divided by
+// 10^exponent of the display unit.
+func NewDecimal(unscaled *big.Int, scale int) Decimal {
+	if scale < 0 {
+		unscaled = new(big.Int).Mul(unscaled, pow10(-scale))
+		scale = 0
+	}
+	return Decimal{unscaled: unscaled, scale: scale}
+}
+
+// maxDecimalExponent bounds the exponent ParseDecimal accepts, which would otherwise
+// let "1e99999999" spend its time and memory on digits.
+const maxDecimalExponent = 100
+
+// ParseDecimal parses numbers like "12", "-0.5", "1000000000000000000.25" and "1.2e-05".
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exponent, err := strconv.Atoi(s[i+1:])
+		mantissa, mantissaErr := ParseDecimal(s[:i])
+		if err != nil || mantissaErr != nil {
+			return Decimal{}, fmt.Errorf("invalid number %q", s)
+		}
+		if exponent < -maxDecimalExponent || exponent > maxDecimalExponent {
+			return Decimal{}, fmt.Errorf("exponent of %q out of range ±%d", s, maxDecimalExponent)
+		}
+		return mantissa.Shift(-exponent), nil
+	}
+	whole, fraction, _ := strings.Cut(s, ".")
+	if strings.TrimLeft(whole, "+-") == "" && fraction == "" || strings.ContainsAny(fraction, "+-") {
+		return Decimal{}, fmt.Errorf("invalid number %q", s)
+	}
+	unscaled, ok := new(big.Int).SetString(whole+fraction, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid number %q", s)
+	}
+	return Decimal{unscaled: unscaled, scale: len(fraction)}, nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// rescale returns the unscaled values of d and e at their common scale.
+func (d Decimal) rescale(e Decimal) (*big.Int, *big.Int, int) {
+	a, b := d.int(), e.int()
+	switch {
+	case d.scale < e.scale:
+		a = new(big.Int).Mul(a, pow10(e.scale-d.scale))
+	case d.scale > e.scale:
+		b = new(big.Int).Mul(b, pow10(d.scale-e.scale))
+	}
+	return a, b, max(d.scale, e.scale)
+}
+
+func (d Decimal) Add(e Decimal) Decimal {
+	a, b, scale := d.rescale(e)
+	return Decimal{unscaled: new(big.Int).Add(a, b), scale: scale}
+}
+
+// Shift moves the decimal point places to the left: 1500000 shifted by 6 is 1.5.
+func (d Decimal) Shift(places int) Decimal {
+	return NewDecimal(d.int(), d.scale+places)
+}
+
+func (d Decimal) Mul(e Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.int(), e.int()), scale: d.scale + e.scale}
+}
+
+// Round rounds to places decimals, halves away from zero.
+func (d Decimal) Round(places int) Decimal {
+	if d.scale <= places {
+		return d
+	}
+	divisor := pow10(d.scale - places)
+	quotient, remainder := new(big.Int).QuoRem(d.int(), divisor, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(divisor) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(d.int().Sign())))
+	}
+	return Decimal{unscaled: quotient, scale: places}
+}
+
+// Cmp returns -1, 0 or 1 as d is less than, equal to or greater than e.
+func (d Decimal) Cmp(e Decimal) int {
+	a, b, _ := d.rescale(e)
+	return a.Cmp(b)
+}
+
+func (d Decimal) IsZero() bool {
+	return d.int().Sign() == 0
+}
+
+// Float64 is the nearest float64, for metrics and other approximate uses.
+func (d Decimal) Float64() float64 {
+	f, _ := new(big.Rat).SetFrac(d.int(), pow10(d.scale)).Float64()
+	return f
+}
+
+// String renders every significant digit without trailing zeros, e.g. "1.5".
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.int()).String()
+	if d.scale > 0 {
+		if len(digits) <= d.scale {
+			digits = strings.Repeat("0", d.scale-len(digits)+1) + digits
+		}
+		point := len(digits) - d.scale
+		digits = strings.TrimRight(digits[:point]+"."+digits[point:], "0")
+		digits = strings.TrimSuffix(digits, ".")
+	}
+	if d.int().Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// MarshalJSON writes a JSON number with every digit; consumers that need them all
+// should decode it as a string or big number.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	parsed, err := ParseDecimal(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (d *Decimal) UnmarshalYAML(node *yaml.Node) error {
+	parsed, err := ParseDecimal(node.Value)
+	if err != nil || node.Kind != yaml.ScalarNode {
+		// A TypeError is reported with the others instead of stopping the decoding.
+		return &yaml.TypeError{Errors: []string{fmt.Sprintf("line %d: cannot unmarshal %q into a decimal number", node.Line, node.Value)}}
+	}
+	*d = parsed
+	return nil
+}
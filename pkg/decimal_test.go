@@ -0,0 +1,138 @@
+package pkg
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"12", "12"},
+		{"-0.5", "-0.5"},
+		{"+3", "3"},
+		{"-1.50", "-1.5"},
+		{"0.000001", "0.000001"},
+		{".5", "0.5"},
+		{"1000000000000000000.25", "1000000000000000000.25"},
+		{"123456789012345678901234567890", "123456789012345678901234567890"},
+		{"1.2e-05", "0.000012"},
+		{"2.5E+7", "25000000"},
+		{"-1e3", "-1000"},
+		{" 7 ", "7"},
+	}
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.in)
+		if err != nil {
+			t.Errorf("ParseDecimal(%q): %v", tt.in, err)
+			continue
+		}
+		if got := d.String(); got != tt.want {
+			t.Errorf("ParseDecimal(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+	for _, in := range []string{"", "-", ".", "abc", "1.-2", "1..2", "1e", "e5", "1,5"} {
+		if d, err := ParseDecimal(in); err == nil {
+			t.Errorf("ParseDecimal(%q) = %s, want an error", in, d)
+		}
+	}
+}
+
+func TestParseDecimalExponentRange(t *testing.T) {
+	for _, s := range []string{"1e100", "1e-100", "2.5E+7"} {
+		if _, err := ParseDecimal(s); err != nil {
+			t.Errorf("ParseDecimal(%q): %v", s, err)
+		}
+	}
+	for _, s := range []string{"1e101", "1e-101", "1e99999999", "1e-99999999"} {
+		if _, err := ParseDecimal(s); err == nil {
+			t.Errorf("ParseDecimal(%q) accepted an out-of-range exponent", s)
+		}
+	}
+}
+
+func TestDecimalShift(t *testing.T) {
+	tests := []struct {
+		unscaled string
+		places   int
+		want     string
+	}{
+		{"1500000", 6, "1.5"}, // 1500000 uatom
+		{"1", 6, "0.000001"},  // below one display unit
+		{"999999", 6, "0.999999"},
+		{"1", 18, "0.000000000000000001"},
+		{"1500000000000000000", 18, "1.5"}, // 18-decimal denoms such as aevmos
+		{"1234567890123456789012345", 18, "1234567.890123456789012345"},
+		{"-500", 6, "-0.0005"},
+		{"15", -2, "1500"},
+		{"0", 6, "0"},
+	}
+	for _, tt := range tests {
+		unscaled, _ := new(big.Int).SetString(tt.unscaled, 10)
+		if got := NewDecimal(unscaled, 0).Shift(tt.places).String(); got != tt.want {
+			t.Errorf("%s shifted by %d = %s, want %s", tt.unscaled, tt.places, got, tt.want)
+		}
+	}
+	if got := (Decimal{}).Shift(6).String(); got != "0" {
+		t.Errorf("zero value shifted = %s, want 0", got)
+	}
+}
+
+func TestDecimalRound(t *testing.T) {
+	tests := []struct {
+		in     string
+		places int
+		want   string
+	}{
+		{"1.005", 2, "1.01"}, // ties away from zero
+		{"-1.005", 2, "-1.01"},
+		{"2.5", 0, "3"},
+		{"-2.5", 0, "-3"},
+		{"1.004", 2, "1"},
+		{"-1.004", 2, "-1"},
+		{"0.994", 2, "0.99"},
+		{"0.995", 2, "1"},
+		{"0.000000000000000001", 6, "0"},
+		{"0.0000005", 6, "0.000001"},
+		{"1.5", 4, "1.5"},
+		{"123.456", 0, "123"},
+	}
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := d.Round(tt.places).String(); got != tt.want {
+			t.Errorf("%s rounded to %d places = %s, want %s", tt.in, tt.places, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalCmp(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.50", "1.5", 0},
+		{"0", "-0.0", 0},
+		{"-1", "0.5", -1},
+		{"0.000000000000000001", "0", 1},
+		{"10", "9.99999", 1},
+		{"-0.000001", "-0.0000001", -1},
+		{"1000000000000000000000", "999999999999999999999.999999999999999999", 1},
+	}
+	for _, tt := range tests {
+		a, errA := ParseDecimal(tt.a)
+		b, errB := ParseDecimal(tt.b)
+		if errA != nil || errB != nil {
+			t.Fatal(errA, errB)
+		}
+		if got := a.Cmp(b); got != tt.want {
+			t.Errorf("Cmp(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := b.Cmp(a); got != -tt.want {
+			t.Errorf("Cmp(%s, %s) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	denomMetadataTTL   = time.Hour
+	denomMetadataRetry = 5 * time.Minute
+)
+
+// DenomMetadata tells how amounts of a base denom such as "ukava" are displayed.
+type DenomMetadata struct {
+	Display     string `yaml:"display"`      // e.g. "kava"
+	Exponent    int    `yaml:"exponent"`     // 1 kava = 10^6 ukava
+	Symbol      string `yaml:"symbol"`       // e.g. "KAVA"
+	CoinGeckoID string `yaml:"coingecko_id"` // for fiat values, e.g. "kava"
+}
+
+// denomMetadataJSON is the metadata of a denom as served by the bank module and as
+// listed in chain-registry asset lists.
+type denomMetadataJSON struct {
+	Base        string `json:"base"`
+	Display     string `json:"display"`
+	Symbol      string `json:"symbol"`
+	CoinGeckoID string `json:"coingecko_id"`
+	DenomUnits  []struct {
+		Denom    string `json:"denom"`
+		Exponent int    `json:"exponent"`
+	} `json:"denom_units"`
+}
+
+func (m denomMetadataJSON) metadata() DenomMetadata {
+	metadata := DenomMetadata{Display: m.Display, Symbol: m.Symbol, CoinGeckoID: m.CoinGeckoID}
+	for _, unit := range m.DenomUnits {
+		if unit.Denom == m.Display {
+			metadata.Exponent = unit.Exponent
+		}
+	}
+	return metadata
+}
+
+// denomMetadataCache keeps the bank denom metadata of every chain API, fetched again
+// after denomMetadataTTL. Fetches run outside the lock, one at a time per API.
+type denomMetadataCache struct {
+	mu      sync.Mutex
+	client  *http.Client
+	chains  map[string]*cachedDenomMetadata
+	flights singleflight.Group
+}
+
+type cachedDenomMetadata struct {
+	expires time.Time
+	denoms  map[string]DenomMetadata
+}
+
+var bankDenoms = &denomMetadataCache{
+	client: &http.Client{Timeout: 15 * time.Second},
+	chains: make(map[string]*cachedDenomMetadata),
+}
+
+// Get returns the denom metadata of the chain served at api. When it cannot be fetched
+// the previous metadata, if any, is kept and the fetch retried after denomMetadataRetry.
+func (c *denomMetadataCache) Get(api string) map[string]DenomMetadata {
+	c.mu.Lock()
+	cached := c.chains[api]
+	c.mu.Unlock()
+	if cached != nil && time.Now().Before(cached.expires) {
+		return cached.denoms
+	}
+	denoms, _, _ := c.flights.Do(api, func() (interface{}, error) {
+		return c.refresh(api), nil
+	})
+	return denoms.(map[string]DenomMetadata)
+}
+
+// refresh fetches the metadata of api and caches it, or keeps the previous metadata
+// when that fails.
+func (c *denomMetadataCache) refresh(api string) map[string]DenomMetadata {
+	denoms, err := c.fetch(api)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		log.Printf("Error fetching denom metadata from %s: %v", api, err)
+		var previous map[string]DenomMetadata
+		if cached := c.chains[api]; cached != nil {
+			previous = cached.denoms
+		}
+		c.chains[api] = &cachedDenomMetadata{expires: time.Now().Add(denomMetadataRetry), denoms: previous}
+		return previous
+	}
+	c.chains[api] = &cachedDenomMetadata{expires: time.Now().Add(denomMetadataTTL), denoms: denoms}
+	return denoms
+}
+
+func (c *denomMetadataCache) fetch(api string) (map[string]DenomMetadata, error) {
+	denoms := make(map[string]DenomMetadata)
+	key := ""
+	for {
+		resp, err := c.client.Get(fmt.Sprintf("%s/cosmos/bank/v1beta1/denoms_metadata?pagination.limit=200&pagination.key=%s", api, url.QueryEscape(key)))
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Metadatas  []denomMetadataJSON `json:"metadatas"`
+			Pagination struct {
+				NextKey string `json:"next_key"`
+			} `json:"pagination"`
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("non-200 status code: %d", resp.StatusCode)
+		}
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, metadata := range page.Metadatas {
+			denoms[metadata.Base] = metadata.metadata()
+		}
+		if page.Pagination.NextKey == "" {
+			return denoms, nil
+		}
+		key = page.Pagination.NextKey
+	}
+}
+
+// denomMetadata tells how to display a base denom of the chain. The chain's denoms in
+// the config come first, then its bank metadata, then the chain registry. IBC vouchers
+// are otherwise named after the base denom they trace back to.
+func (c ChainConfig) denomMetadata(base string) DenomMetadata {
+	metadata, ok := c.lookupDenomMetadata(base)
+	if !ok && strings.HasPrefix(base, "ibc/") {
+		if trace, traced := denomTraces.Get(c.API, base); traced {
+			base = trace.BaseDenom
+			metadata, ok = c.lookupDenomMetadata(base)
+			if !ok {
+				metadata, ok = registryAssetMetadata(c.registryPath, base)
+			}
+		}
+	}
+	if !ok {
+		metadata = defaultDenomMetadata(base)
+	}
+	if metadata.Display == "" {
+		metadata.Display = base
+	}
+	return metadata
+}
+
+func (c ChainConfig) lookupDenomMetadata(base string) (DenomMetadata, bool) {
+	metadata, ok := c.Denoms[base]
+	if !ok && c.API != "" {
+		metadata, ok = bankDenoms.Get(c.API)[base]
+	}
+	if !ok {
+		metadata, ok = c.registryDenoms[base]
+	}
+	return metadata, ok
+}
+
+// defaultDenomMetadata takes denoms with a "u" prefix to be micro units and shows the
+// rest as they are.
+func defaultDenomMetadata(base string) DenomMetadata {
+	if len(base) > 1 && strings.HasPrefix(base, "u") && !strings.Contains(base, "/") {
+		return DenomMetadata{Display: strings.TrimPrefix(base, "u"), Exponent: 6}
+	}
+	return DenomMetadata{Display: base}
+}
+
+// displayCoin converts an amount of a base denom, e.g. 1500000 ukava, to display
+// units: 1.5 kava.
+func (c ChainConfig) displayCoin(amount Amount) Coin {
+	metadata := c.denomMetadata(amount.Denom)
+	value, err := ParseDecimal(amount.Amount)
+	if err != nil {
+		log.Printf("Invalid amount of %s: %v", amount.Denom, err)
+	}
+	coin := Coin{Denom: metadata.Display, Amount: value.Shift(metadata.Exponent), coinGeckoID: metadata.CoinGeckoID}
+	if strings.HasPrefix(amount.Denom, "ibc/") {
+		coin.IBCDenom = amount.Denom
+		if trace, ok := denomTraces.Get(c.API, amount.Denom); ok {
+			coin.IBCPath = trace.Path
+		}
+	}
+	return coin
+}
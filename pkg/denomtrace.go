@@ -0,0 +1,223 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const denomTraceRetry = 5 * time.Minute
+
+// DenomTrace is where an IBC voucher comes from: ibc/27394FB0… is uatom received over
+// transfer/channel-0.
+type DenomTrace struct {
+	Path      string `json:"path"`
+	BaseDenom string `json:"base_denom"`
+}
+
+// denomTraceCache keeps the traces of IBC denoms by hash. A hash stands for the same
+// path and base denom on every chain and never changes, so traces are kept for good
+// and, with a file set, persisted across restarts. Lookups run outside the lock, one
+// at a time per hash.
+type denomTraceCache struct {
+	mu      sync.Mutex
+	client  *http.Client
+	file    string
+	traces  map[string]DenomTrace
+	failed  map[string]time.Time // hashes whose lookup failed, retried after denomTraceRetry
+	flights singleflight.Group
+}
+
+var denomTraces = &denomTraceCache{
+	client: &http.Client{Timeout: 15 * time.Second},
+	traces: make(map[string]DenomTrace),
+	failed: make(map[string]time.Time),
+}
+
+// SetFile loads the traces persisted in file and saves new ones there.
+func (c *denomTraceCache) SetFile(file string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if file == c.file {
+		return nil
+	}
+	c.file = file
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return c.save()
+	}
+	if err != nil {
+		return err
+	}
+	var traces map[string]DenomTrace
+	if err := json.Unmarshal(data, &traces); err != nil {
+		return fmt.Errorf("%s: %v", file, err)
+	}
+	for hash, trace := range traces {
+		c.traces[hash] = trace
+	}
+	return nil
+}
+
+// save writes the traces to the file, replacing it at once so that a crash cannot
+// leave it half written.
+func (c *denomTraceCache) save() error {
+	if c.file == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(c.file), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c.traces, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := c.file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.file)
+}
+
+// Get returns the trace of an IBC denom ("ibc/<hash>"), asking the chain at api when it
+// is not cached.
+func (c *denomTraceCache) Get(api string, denom string) (DenomTrace, bool) {
+	hash := strings.ToUpper(strings.TrimPrefix(denom, "ibc/"))
+	c.mu.Lock()
+	trace, ok := c.traces[hash]
+	failedAt := c.failed[hash]
+	c.mu.Unlock()
+
+	if ok {
+		return trace, true
+	}
+	if api == "" || time.Since(failedAt) < denomTraceRetry {
+		return DenomTrace{}, false
+	}
+	resolved, err, _ := c.flights.Do(hash, func() (interface{}, error) {
+		return c.resolve(api, denom, hash)
+	})
+	if err != nil {
+		return DenomTrace{}, false
+	}
+	return resolved.(DenomTrace), true
+}
+
+// resolve fetches the trace of hash and caches the trace or the failure.
+func (c *denomTraceCache) resolve(api string, denom string, hash string) (DenomTrace, error) {
+	trace, err := c.fetch(api, hash)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		log.Printf("Error resolving %s on %s: %v", denom, api, err)
+		c.failed[hash] = time.Now()
+		return DenomTrace{}, err
+	}
+	delete(c.failed, hash)
+	c.traces[hash] = trace
+	if err := c.save(); err != nil {
+		log.Printf("Error saving denom traces: %v", err)
+	}
+	return trace, nil
+}
+
+// fetch asks the transfer module for a trace, through the denom_traces endpoint of
+// ibc-go up to v7 or the denoms endpoint that replaced it.
+func (c *denomTraceCache) fetch(api string, hash string) (DenomTrace, error) {
+	var v1 struct {
+		DenomTrace DenomTrace `json:"denom_trace"`
+	}
+	err := c.getJSON(fmt.Sprintf("%s/ibc/apps/transfer/v1/denom_traces/%s", api, hash), &v1)
+	if err == nil && v1.DenomTrace.BaseDenom != "" {
+		return v1.DenomTrace, nil
+	}
+
+	var v2 struct {
+		Denom struct {
+			Base  string `json:"base"`
+			Trace []struct {
+				PortID    string `json:"port_id"`
+				ChannelID string `json:"channel_id"`
+			} `json:"trace"`
+		} `json:"denom"`
+	}
+	if err := c.getJSON(fmt.Sprintf("%s/ibc/apps/transfer/v1/denoms/%s", api, hash), &v2); err != nil {
+		return DenomTrace{}, err
+	}
+	if v2.Denom.Base == "" {
+		return DenomTrace{}, fmt.Errorf("no trace found")
+	}
+	var path []string
+	for _, hop := range v2.Denom.Trace {
+		path = append(path, hop.PortID+"/"+hop.ChannelID)
+	}
+	return DenomTrace{Path: strings.Join(path, "/"), BaseDenom: v2.Denom.Base}, nil
+}
+
+func (c *denomTraceCache) getJSON(url string, value interface{}) error {
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("non-200 status code: %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(value)
+}
+
+// registryAssets indexes the native assets of every chain in a chain-registry checkout
+// by base denom, to name the base denoms of IBC vouchers. Each checkout is read once.
+var registryAssets = struct {
+	sync.Mutex
+	checkouts map[string]map[string]DenomMetadata
+}{checkouts: make(map[string]map[string]DenomMetadata)}
+
+func registryAssetMetadata(registryPath string, base string) (DenomMetadata, bool) {
+	if registryPath == "" {
+		return DenomMetadata{}, false
+	}
+	registryAssets.Lock()
+	defer registryAssets.Unlock()
+
+	assets, ok := registryAssets.checkouts[registryPath]
+	if !ok {
+		assets = make(map[string]DenomMetadata)
+		files, _ := filepath.Glob(filepath.Join(registryPath, "*", "assetlist.json"))
+		sort.Strings(files)
+		for _, file := range files {
+			var list registryAssetList
+			if err := readJSONFile(file, &list); err != nil {
+				log.Printf("Error reading %v", err)
+				continue
+			}
+			for _, asset := range list.Assets {
+				if _, seen := assets[asset.Base]; !seen && !strings.HasPrefix(asset.Base, "ibc/") {
+					assets[asset.Base] = asset.metadata()
+				}
+			}
+		}
+		registryAssets.checkouts[registryPath] = assets
+	}
+	metadata, ok := assets[base]
+	return metadata, ok
+}
+
+// shortDenom shortens the hash of an IBC denom: ibc/2739…5EB2.
+func shortDenom(denom string) string {
+	if !strings.HasPrefix(denom, "ibc/") || len(denom) < 16 {
+		return denom
+	}
+	return denom[:8] + "…" + denom[len(denom)-4:]
+}
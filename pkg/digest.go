@@ -0,0 +1,129 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Digest batches a route's alerts into one summary per interval. Alerts matching
+// Bypass are still sent immediately.
+type Digest struct {
+	Interval string  `yaml:"interval"` // "hourly", "daily" or a duration such as "30m"
+	Bypass   *Filter `yaml:"bypass"`
+
+	interval time.Duration
+}
+
+func (d *Digest) compile() error {
+	if d == nil {
+		return nil
+	}
+	switch d.Interval {
+	case "hourly":
+		d.interval = time.Hour
+	case "daily":
+		d.interval = 24 * time.Hour
+	default:
+		interval, err := time.ParseDuration(d.Interval)
+		if err != nil || interval <= 0 {
+			return fmt.Errorf("invalid digest interval %q", d.Interval)
+		}
+		d.interval = interval
+	}
+	return d.Bypass.compile()
+}
+
+// bypasses reports whether an alert is critical enough to skip the digest.
+func (d *Digest) bypasses(alertData AlertData) bool {
+	return d.Bypass != nil && d.Bypass.Match(alertData)
+}
+
+// digester accumulates alerts and flushes them at interval boundaries (UTC), so an
+// hourly digest goes out on the hour and a daily one at midnight.
+type digester struct {
+	title     string
+	interval  time.Duration
+	notifiers []SummaryNotifier
+
+	mu     sync.Mutex
+	start  time.Time
+	alerts []AlertData
+	stop   chan struct{}
+}
+
+func newDigester(title string, interval time.Duration, notifiers []SummaryNotifier) *digester {
+	d := &digester{
+		title:     title,
+		interval:  interval,
+		notifiers: notifiers,
+		start:     time.Now().Truncate(interval),
+		stop:      make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+func (d *digester) Add(alertData AlertData) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.alerts = append(d.alerts, alertData)
+}
+
+func (d *digester) run() {
+	for {
+		next := time.Now().Truncate(d.interval).Add(d.interval)
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+			d.flush(next)
+		case <-d.stop:
+			timer.Stop()
+			d.flush(time.Now())
+			return
+		}
+	}
+}
+
+// takeOver moves the alerts collected by a digester being replaced into d.
+func (d *digester) takeOver(old *digester) {
+	old.mu.Lock()
+	alerts, start := old.alerts, old.start
+	old.alerts = nil
+	old.mu.Unlock()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.alerts = append(alerts, d.alerts...)
+	if start.Before(d.start) {
+		d.start = start
+	}
+}
+
+// Stop sends what has been collected so far and stops the digester.
+func (d *digester) Stop() {
+	close(d.stop)
+}
+
+func (d *digester) flush(end time.Time) {
+	d.mu.Lock()
+	alerts, start := d.alerts, d.start
+	d.alerts, d.start = nil, end
+	d.mu.Unlock()
+
+	if len(alerts) == 0 {
+		return
+	}
+	summary := NewSummary(d.title, start, end, alerts)
+	for _, notifier := range d.notifiers {
+		if err := notifier.NotifySummary(summary); errors.Is(err, errQueued) {
+			log.Printf("Digest of %d alerts to %s queued by its rate limit", len(alerts), notifier.Name())
+		} else if err != nil {
+			log.Printf("Error sending digest to %s: %v", notifier.Name(), err)
+		} else {
+			log.Printf("Digest of %d alerts sent to %s successfully", len(alerts), notifier.Name())
+		}
+	}
+}
@@ -28,15 +28,45 @@ type EmbedField struct {
 	Inline bool   `json:"inline"`
 }
 
+// Discord rejects embeds with more than 25 fields.
+const discordMaxEmbedFields = 25
+
 func SendDiscordWebhook(webhookURL string, alertData AlertData) error {
-	url := fmt.Sprintf("%s%s", alertData.ExplorerURL, alertData.TxHash)
+	webhook := DiscordWebhook{
+		Username: "Transaction Bot",
+		Embeds:   []Embed{buildDiscordEmbed(alertData)},
+	}
+
+	jsonBytes, err := json.Marshal(webhook)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", webhookURL, bytes.NewBuffer(jsonBytes))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+func buildDiscordEmbed(alertData AlertData) Embed {
+	url := alertData.TxURL()
 
 	fields := []EmbedField{}
 
-	for _, detail := range alertData.MessageDetails {
+	for _, detail := range alertData.allMessages() {
 		fields = append(fields, EmbedField{Name: "\u200B", Value: "\u200B", Inline: false})
 		fields = append(fields, EmbedField{
-			Name:   fmt.Sprintf("#%d %s", detail.Index, detail.Action),
+			Name:   detail.Title(),
 			Value:  "_ _", // Empty value to just show the action and index
 			Inline: false,
 		})
@@ -57,44 +87,29 @@ func SendDiscordWebhook(webhookURL string, alertData AlertData) error {
 		memoText = fmt.Sprintf("Memo : `%s`", alertData.Memo)
 	}
 
-	description := fmt.Sprintf("[Txs Hash](%s) : *`%s`*\nHeight : `%s`\nFees : `%s`\n%s\n", url, alertData.TxHash, alertData.Height, alertData.Fees, memoText)
+	feesText := fmt.Sprintf("Fees : `%s`", alertData.Fees)
+	if paidBy := alertData.feePaidBy(); paidBy != "" {
+		feesText += fmt.Sprintf("\nFee Paid By : `%s`", paidBy)
+	}
+
+	description := fmt.Sprintf("[Txs Hash](%s) : *`%s`*\nHeight : `%s`\n%s\n%s\n", url, alertData.TxHash, alertData.Height, feesText, memoText)
 
 	if alertData.Error != "" {
 		color = 16711680 // Red
 		description += fmt.Sprintf("Error : `%s`\n", alertData.Error)
 	}
-	embed := Embed{
-		Title:       fmt.Sprintf("%s New Transaction (<t:%d>)", alertData.ChainName, convertToUnixTimestamp(alertData.Timestamp)),
+	if len(fields) > discordMaxEmbedFields {
+		fields = fields[:discordMaxEmbedFields-1]
+		fields = append(fields, EmbedField{Name: "…", Value: "Remaining details truncated", Inline: false})
+	}
+
+	return Embed{
+		Title:       fmt.Sprintf("%s (<t:%d>)", alertData.heading(), convertToUnixTimestamp(alertData.Timestamp)),
 		Description: description,
 
 		Fields: fields,
 		Color:  color,
 	}
-
-	webhook := DiscordWebhook{
-		Username: "Transaction Bot",
-		Embeds:   []Embed{embed},
-	}
-
-	jsonBytes, err := json.Marshal(webhook)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest("POST", webhookURL, bytes.NewBuffer(jsonBytes))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	return nil
 }
 
 func convertToUnixTimestamp(isoTimestamp string) int64 {
@@ -105,3 +120,23 @@ func convertToUnixTimestamp(isoTimestamp string) int64 {
 	}
 	return parsedTime.Unix()
 }
+
+func SendDiscordSummary(webhookURL string, summary Summary) error {
+	webhook := DiscordWebhook{
+		Username: "Transaction Bot",
+		Embeds:   []Embed{buildDiscordSummaryEmbed(summary)},
+	}
+	return postJSON("POST", webhookURL, webhook, nil)
+}
+
+func buildDiscordSummaryEmbed(summary Summary) Embed {
+	color := 3447003 // Blue
+	if len(summary.Failures) > 0 {
+		color = 16711680 // Red
+	}
+	return Embed{
+		Title:       summary.Title,
+		Description: truncate(summary.Text("", markdownLink), 4000),
+		Color:       color,
+	}
+}
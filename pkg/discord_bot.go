@@ -0,0 +1,380 @@
+package pkg
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	discordAPIURL          = "https://discord.com/api/v10"
+	discordMaxContent      = 2000
+	discordMaxThreadName   = 100 // characters
+	discordChannelForum    = 15
+	discordChannelMedia    = 16
+	discordPublicThread    = 11
+	discordThreadArchive   = 10080 // minutes, one week
+	discordInteractionPing = 1
+	discordInteractionCmd  = 2
+)
+
+var discordMarkup = chatMarkup{bold: "**", renderAlert: func(alertData AlertData) string {
+	embed := buildDiscordEmbed(alertData)
+	return embed.Title + "\n" + embed.Description
+}}
+
+// discordClient is a minimal REST client authenticated with a bot token.
+type discordClient struct {
+	botToken string
+	http     *http.Client
+}
+
+func newDiscordClient(botToken string) *discordClient {
+	return &discordClient{botToken: botToken, http: &http.Client{Timeout: 15 * time.Second}}
+}
+
+// do sends a REST request, waiting and retrying once when Discord answers 429.
+func (c *discordClient) do(method string, path string, payload interface{}, out interface{}) error {
+	var body []byte
+	if payload != nil {
+		var err error
+		if body, err = json.Marshal(payload); err != nil {
+			return err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(method, discordAPIURL+path, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bot "+c.botToken)
+		if payload != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return err
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt == 0 {
+			var rateLimit struct {
+				RetryAfter float64 `json:"retry_after"`
+			}
+			json.Unmarshal(respBody, &rateLimit)
+			time.Sleep(time.Duration(rateLimit.RetryAfter*float64(time.Second)) + 100*time.Millisecond)
+			continue
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("discord %s %s: status %d: %s", method, path, resp.StatusCode, truncate(string(respBody), 200))
+		}
+		if out != nil {
+			return json.Unmarshal(respBody, out)
+		}
+		return nil
+	}
+}
+
+type discordChannel struct {
+	ID       string `json:"id"`
+	Type     int    `json:"type"`
+	Name     string `json:"name"`
+	ParentID string `json:"parent_id"`
+}
+
+type discordMessage struct {
+	Content string  `json:"content,omitempty"`
+	Embeds  []Embed `json:"embeds,omitempty"`
+}
+
+// discordBotNotifier posts alerts with a bot token, optionally into one thread (or
+// forum post) per wallet so that busy channels stay readable.
+type discordBotNotifier struct {
+	client          *discordClient
+	guildID         string
+	channelID       string
+	threadPerWallet bool
+
+	mu      sync.Mutex
+	forum   *bool
+	threads map[string]string // thread name -> thread ID
+}
+
+func NewDiscordBotNotifier(botToken string, guildID string, channelID string, threadPerWallet bool) Notifier {
+	return &discordBotNotifier{
+		client:          newDiscordClient(botToken),
+		guildID:         guildID,
+		channelID:       channelID,
+		threadPerWallet: threadPerWallet,
+		threads:         make(map[string]string),
+	}
+}
+
+func (n *discordBotNotifier) Name() string {
+	return "Discord Bot"
+}
+
+func (n *discordBotNotifier) Notify(alertData AlertData) error {
+	message := discordMessage{Embeds: []Embed{buildDiscordEmbed(alertData)}}
+	if !n.threadPerWallet {
+		return n.client.do("POST", "/channels/"+n.channelID+"/messages", message, nil)
+	}
+
+	threadID, posted, err := n.walletThread(alertData, message)
+	if err != nil {
+		return err
+	}
+	if posted {
+		return nil
+	}
+	return n.client.do("POST", "/channels/"+threadID+"/messages", message, nil)
+}
+
+// walletThread returns the thread of the alert's wallet, creating it if needed. Creating
+// a forum post requires its first message, in which case posted is true.
+func (n *discordBotNotifier) walletThread(alertData AlertData, message discordMessage) (threadID string, posted bool, err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	name := fmt.Sprintf("%s · %s", alertData.ChainName, addressBook.Display(alertData.WalletAddress))
+	if runes := []rune(name); len(runes) > discordMaxThreadName {
+		name = string(runes[:discordMaxThreadName])
+	}
+	if threadID, ok := n.threads[name]; ok {
+		return threadID, false, nil
+	}
+
+	if n.forum == nil {
+		var channel discordChannel
+		if err := n.client.do("GET", "/channels/"+n.channelID, nil, &channel); err != nil {
+			return "", false, err
+		}
+		forum := channel.Type == discordChannelForum || channel.Type == discordChannelMedia
+		n.forum = &forum
+	}
+
+	// Reuse a thread that survived a restart.
+	if n.guildID != "" {
+		var active struct {
+			Threads []discordChannel `json:"threads"`
+		}
+		if err := n.client.do("GET", "/guilds/"+n.guildID+"/threads/active", nil, &active); err != nil {
+			log.Printf("Error listing active Discord threads: %v", err)
+		}
+		for _, thread := range active.Threads {
+			if thread.ParentID == n.channelID && thread.Name == name {
+				n.threads[name] = thread.ID
+				return thread.ID, false, nil
+			}
+		}
+	}
+
+	var thread discordChannel
+	if *n.forum {
+		err = n.client.do("POST", "/channels/"+n.channelID+"/threads", map[string]interface{}{
+			"name":                  name,
+			"auto_archive_duration": discordThreadArchive,
+			"message":               message,
+		}, &thread)
+		posted = true
+	} else {
+		err = n.client.do("POST", "/channels/"+n.channelID+"/threads", map[string]interface{}{
+			"name":                  name,
+			"type":                  discordPublicThread,
+			"auto_archive_duration": discordThreadArchive,
+		}, &thread)
+	}
+	if err != nil {
+		return "", false, err
+	}
+	n.threads[name] = thread.ID
+	return thread.ID, posted, nil
+}
+
+type discordInteraction struct {
+	Type   int    `json:"type"`
+	Token  string `json:"token"`
+	Member *struct {
+		User discordUser `json:"user"`
+	} `json:"member"`
+	User *discordUser `json:"user"`
+	Data struct {
+		Name    string `json:"name"`
+		Options []struct {
+			Name  string          `json:"name"`
+			Value json.RawMessage `json:"value"`
+		} `json:"options"`
+	} `json:"data"`
+}
+
+type discordUser struct {
+	ID string `json:"id"`
+}
+
+type discordCommandOption struct {
+	Type        int    `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+type discordCommand struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Options     []discordCommandOption `json:"options,omitempty"`
+}
+
+const (
+	discordOptionString  = 3
+	discordOptionInteger = 4
+)
+
+// discordCommands mirrors the Telegram commands; option order matches runCommand's args.
+var discordCommands = []discordCommand{
+	{Name: "status", Description: "Connection state per chain"},
+	{Name: "wallets", Description: "Monitored wallets"},
+	{Name: "last", Description: "Recent alerts of a chain", Options: []discordCommandOption{
+		{Type: discordOptionString, Name: "chain", Description: "Chain name", Required: true},
+		{Type: discordOptionInteger, Name: "count", Description: "Number of alerts"},
+	}},
+	{Name: "mute", Description: "Silence a wallet", Options: []discordCommandOption{
+		{Type: discordOptionString, Name: "wallet", Description: "Wallet address", Required: true},
+		{Type: discordOptionString, Name: "duration", Description: "Duration, e.g. 2h", Required: true},
+	}},
+	{Name: "unmute", Description: "Lift a mute", Options: []discordCommandOption{
+		{Type: discordOptionString, Name: "wallet", Description: "Wallet address", Required: true},
+	}},
+	{Name: "tx", Description: "Render any transaction", Options: []discordCommandOption{
+		{Type: discordOptionString, Name: "chain", Description: "Chain name", Required: true},
+		{Type: discordOptionString, Name: "hash", Description: "Transaction hash", Required: true},
+	}},
+}
+
+// RunDiscordInteractions registers the slash commands and serves the interactions endpoint.
+func RunDiscordInteractions(cfg *Config) {
+	bot := cfg.Alerting.Discord.Bot
+	publicKey, err := hex.DecodeString(bot.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		log.Printf("Invalid Discord public key, interactions endpoint disabled")
+		return
+	}
+	client := newDiscordClient(bot.BotToken)
+
+	path := "/applications/" + bot.ApplicationID + "/commands"
+	if bot.GuildID != "" {
+		path = "/applications/" + bot.ApplicationID + "/guilds/" + bot.GuildID + "/commands"
+	}
+	if err := client.do("PUT", path, discordCommands, nil); err != nil {
+		log.Printf("Error registering Discord commands: %v", err)
+	}
+
+	authorized := make(map[string]bool)
+	for _, userID := range bot.AuthorizedUserIDs {
+		authorized[userID] = true
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/interactions", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		signature, err := hex.DecodeString(r.Header.Get("X-Signature-Ed25519"))
+		message := append([]byte(r.Header.Get("X-Signature-Timestamp")), body...)
+		if err != nil || !ed25519.Verify(publicKey, message, signature) {
+			http.Error(w, "invalid request signature", http.StatusUnauthorized)
+			return
+		}
+
+		var interaction discordInteraction
+		if err := json.Unmarshal(body, &interaction); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch interaction.Type {
+		case discordInteractionPing:
+			w.Write([]byte(`{"type":1}`))
+		case discordInteractionCmd:
+			userID := ""
+			if interaction.Member != nil {
+				userID = interaction.Member.User.ID
+			} else if interaction.User != nil {
+				userID = interaction.User.ID
+			}
+			if userID == "" || !authorized[userID] {
+				w.Write([]byte(`{"type":4,"data":{"content":"You are not allowed to use this bot.","flags":64}}`))
+				return
+			}
+			// Defer the reply since fetching a transaction can exceed Discord's 3s limit.
+			w.Write([]byte(`{"type":5}`))
+			go answerDiscordInteraction(currentConfig(), client, interaction)
+		default:
+			http.Error(w, "unsupported interaction", http.StatusBadRequest)
+		}
+	})
+
+	log.Printf("Serving Discord interactions on %s/interactions", bot.Listen)
+	if err := http.ListenAndServe(bot.Listen, mux); err != nil {
+		log.Printf("Discord interactions endpoint stopped: %v", err)
+	}
+}
+
+func answerDiscordInteraction(cfg *Config, client *discordClient, interaction discordInteraction) {
+	values := make(map[string]string)
+	for _, option := range interaction.Data.Options {
+		var value string
+		if err := json.Unmarshal(option.Value, &value); err != nil {
+			value = strings.TrimSpace(string(option.Value))
+		}
+		values[option.Name] = value
+	}
+	var args []string
+	for _, command := range discordCommands {
+		if command.Name != interaction.Data.Name {
+			continue
+		}
+		for _, option := range command.Options {
+			if value, ok := values[option.Name]; ok {
+				args = append(args, value)
+			}
+		}
+	}
+
+	message := discordMessage{}
+	if interaction.Data.Name == "tx" && len(args) == 2 {
+		if alertData, reply := commandTx(cfg, args[0], args[1]); reply != "" {
+			message.Content = reply
+		} else {
+			message.Embeds = []Embed{buildDiscordEmbed(alertData)}
+		}
+	} else {
+		message.Content = truncate(runCommand(cfg, discordMarkup, interaction.Data.Name, args), discordMaxContent)
+	}
+
+	path := "/webhooks/" + cfg.Alerting.Discord.Bot.ApplicationID + "/" + interaction.Token + "/messages/@original"
+	if err := client.do("PATCH", path, message, nil); err != nil {
+		log.Printf("Error answering Discord interaction: %v", err)
+	}
+}
+
+func (n *discordBotNotifier) NotifySummary(summary Summary) error {
+	message := discordMessage{Embeds: []Embed{buildDiscordSummaryEmbed(summary)}}
+	return n.client.do("POST", "/channels/"+n.channelID+"/messages", message, nil)
+}
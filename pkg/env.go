@@ -0,0 +1,228 @@
+package pkg
+
+import (
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts the environment variables that override config values, e.g.
+// TXMON_ALERTING_TELEGRAM_BOT_TOKEN for alerting.telegram.bot_token.
+const EnvPrefix = "TXMON"
+
+var envReference = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// interpolateEnv replaces ${VAR} and ${VAR:-default} in every scalar value; $${VAR}
+// stands for a literal ${VAR}.
+func (v *validator) interpolateEnv(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode {
+		if !strings.Contains(node.Value, "${") {
+			return
+		}
+		node.Value = envReference.ReplaceAllStringFunc(node.Value, func(reference string) string {
+			if strings.HasPrefix(reference, "$$") {
+				return reference[1:]
+			}
+			match := envReference.FindStringSubmatch(reference)
+			if value, ok := os.LookupEnv(match[1]); ok {
+				return value
+			}
+			if match[2] == "" {
+				v.nodef(node, nil, "environment variable %s is not set", match[1])
+			}
+			return match[3]
+		})
+		return
+	}
+	for i, child := range node.Content {
+		if node.Kind == yaml.MappingNode && i%2 == 0 {
+			continue // keys
+		}
+		v.interpolateEnv(child)
+	}
+}
+
+// resolveSecretFiles replaces "<key>_file: path" with "<key>: <file content>" for every
+// string field of t, so that secrets can be mounted as files.
+func (v *validator) resolveSecretFiles(node *yaml.Node, t reflect.Type, path []interface{}) {
+	if node.Kind == yaml.DocumentNode {
+		for _, child := range node.Content {
+			v.resolveSecretFiles(child, t, path)
+		}
+		return
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			name := strings.TrimSuffix(key.Value, "_file")
+			if field, ok := fields[name]; ok && name != key.Value && field.Type.Kind() == reflect.String {
+				if _, isField := fields[key.Value]; isField {
+					continue
+				}
+				if mappingValue(node, name) != nil {
+					v.nodef(key, path, "both %s and %s are set", name, key.Value)
+					continue
+				}
+				secret, err := os.ReadFile(value.Value)
+				if err != nil {
+					v.nodef(value, append(path[:len(path):len(path)], key.Value), "%v", err)
+				}
+				key.Value = name
+				value.SetString(strings.TrimRight(string(secret), "\r\n"))
+				continue
+			}
+			if field, ok := fields[key.Value]; ok {
+				v.resolveSecretFiles(value, field.Type, append(path[:len(path):len(path)], key.Value))
+			}
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v.resolveSecretFiles(node.Content[i+1], t.Elem(), append(path[:len(path):len(path)], node.Content[i].Value))
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			return
+		}
+		for i, item := range node.Content {
+			v.resolveSecretFiles(item, t.Elem(), append(path[:len(path):len(path)], i))
+		}
+	}
+}
+
+// applyEnvOverrides sets config values from TXMON_* environment variables. Names are
+// the uppercased YAML path joined by underscores (TXMON_CHAINS_KAVA_RPC); lists of
+// strings are comma-separated and string values can be read from the file named by
+// <name>_FILE. Lists of objects, such as routes, cannot be overridden.
+func (v *validator) applyEnvOverrides(node *yaml.Node, t reflect.Type, prefix string, environ map[string]string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		fields := yamlFields(t)
+		for _, name := range sortedKeys(fields) {
+			field := fields[name]
+			envName := prefix + "_" + envKey(name)
+			value := mappingValue(node, name)
+			override, ok := environ[envName]
+			if secretFile, isFile := environ[envName+"_FILE"]; isFile && !ok && field.Type.Kind() == reflect.String {
+				secret, err := os.ReadFile(secretFile)
+				if err != nil {
+					v.nodef(nil, nil, "%s_FILE: %v", envName, err)
+				}
+				override, ok = strings.TrimRight(string(secret), "\r\n"), true
+			}
+			if ok && isEnvScalar(field.Type) {
+				if value == nil {
+					value = addMappingValue(node, name)
+				}
+				setEnvValue(value, field.Type, override)
+				continue
+			}
+			if isEnvScalar(field.Type) || !hasEnvPrefix(environ, envName+"_") {
+				continue
+			}
+			if value == nil {
+				value = addMappingValue(node, name)
+			}
+			v.applyEnvOverrides(value, field.Type, envName, environ)
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			envName := prefix + "_" + envKey(node.Content[i].Value)
+			if override, ok := environ[envName]; ok && isEnvScalar(t.Elem()) {
+				setEnvValue(node.Content[i+1], t.Elem(), override)
+			} else if hasEnvPrefix(environ, envName+"_") {
+				v.applyEnvOverrides(node.Content[i+1], t.Elem(), envName, environ)
+			}
+		}
+	}
+}
+
+// envKey uppercases a YAML key and replaces anything but letters and digits with "_".
+func envKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+func isEnvScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+func setEnvValue(node *yaml.Node, t reflect.Type, value string) {
+	if t.Kind() == reflect.Slice {
+		*node = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: node.Line}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: item})
+			}
+		}
+		return
+	}
+	*node = yaml.Node{Kind: yaml.ScalarNode, Value: value, Line: node.Line}
+	if t.Kind() == reflect.String {
+		node.Tag = "!!str"
+	}
+}
+
+// addMappingValue appends an empty key to a mapping, turning an empty node into one.
+func addMappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		*node = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: node.Line}
+	}
+	value := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: node.Line}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key, Line: node.Line}, value)
+	return value
+}
+
+func hasEnvPrefix(environ map[string]string, prefix string) bool {
+	for name := range environ {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// configEnviron returns the TXMON_* environment variables.
+func configEnviron() map[string]string {
+	environ := make(map[string]string)
+	for _, entry := range os.Environ() {
+		if name, value, ok := strings.Cut(entry, "="); ok && strings.HasPrefix(name, EnvPrefix+"_") {
+			environ[name] = value
+		}
+	}
+	return environ
+}
@@ -0,0 +1,86 @@
+package pkg
+
+import (
+	"strings"
+	"time"
+)
+
+// FeeAllowance holds the fields of the fee allowance types of the SDK. Periodic and
+// allowed-message allowances wrap another allowance.
+type FeeAllowance struct {
+	Type string `json:"@type"`
+	// BasicAllowance
+	SpendLimit []Amount `json:"spend_limit,omitempty"`
+	Expiration *string  `json:"expiration,omitempty"`
+	// PeriodicAllowance
+	Basic            *FeeAllowance `json:"basic,omitempty"`
+	Period           string        `json:"period,omitempty"`
+	PeriodSpendLimit []Amount      `json:"period_spend_limit,omitempty"`
+	// AllowedMsgAllowance
+	Allowance       *FeeAllowance `json:"allowance,omitempty"`
+	AllowedMessages []string      `json:"allowed_messages,omitempty"`
+}
+
+// populateFeegrantDetails adds what a MsgGrantAllowance allows the grantee to spend on
+// fees. Granter and grantee are added with the other addresses.
+func populateFeegrantDetails(details *MessageDetail, message Message, chain ChainConfig) {
+	if message.Type != "/cosmos.feegrant.v1beta1.MsgGrantAllowance" || message.Allowance == nil {
+		return
+	}
+	var limits []string
+	var allowedMessages []string
+	var expiration *string
+	for allowance := message.Allowance; allowance != nil; {
+		switch allowance.Type {
+		case "/cosmos.feegrant.v1beta1.AllowedMsgAllowance":
+			allowedMessages = allowance.AllowedMessages
+			allowance = allowance.Allowance
+		case "/cosmos.feegrant.v1beta1.PeriodicAllowance":
+			if coins := chain.displayCoins(allowance.PeriodSpendLimit); len(coins) > 0 {
+				period := allowance.Period
+				if parsed, err := time.ParseDuration(period); err == nil {
+					period = formatPeriod(parsed)
+				}
+				limits = append(limits, formatCoins(coins)+" per "+period)
+			}
+			allowance = allowance.Basic
+		default:
+			if coins := chain.displayCoins(allowance.SpendLimit); len(coins) > 0 {
+				limits = append(limits, "up to "+formatCoins(coins))
+			}
+			expiration = allowance.Expiration
+			allowance = nil
+		}
+	}
+
+	limit := "unlimited"
+	if len(limits) > 0 {
+		limit = strings.Join(limits, ", ")
+	}
+	appendIfNotNil(&details.Details, "Fee Allowance", &limit)
+	if len(allowedMessages) > 0 {
+		allowed := strings.Join(allowedMessages, ", ")
+		appendIfNotNil(&details.Details, "Allowed Messages", &allowed)
+	}
+	expires := "never"
+	if expiration != nil {
+		expires = *expiration
+		if parsed, err := time.Parse(time.RFC3339, expires); err == nil {
+			expires = parsed.UTC().Format("2006-01-02 15:04 MST")
+		}
+	}
+	appendIfNotNil(&details.Details, "Expiration", &expires)
+}
+
+// formatPeriod renders a period without its zero parts: 86400s is shown as 24h rather
+// than 24h0m0s, and 5400s as 1h30m.
+func formatPeriod(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
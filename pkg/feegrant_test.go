@@ -0,0 +1,26 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatPeriod(t *testing.T) {
+	tests := []struct {
+		seconds int
+		want    string
+	}{
+		{30, "30s"},
+		{90, "1m30s"},
+		{600, "10m"},
+		{3600, "1h"},
+		{3630, "1h0m30s"},
+		{5400, "1h30m"},
+		{86400, "24h"},
+	}
+	for _, tt := range tests {
+		if got := formatPeriod(time.Duration(tt.seconds) * time.Second); got != tt.want {
+			t.Errorf("formatPeriod(%ds) = %q, want %q", tt.seconds, got, tt.want)
+		}
+	}
+}
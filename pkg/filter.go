@@ -0,0 +1,174 @@
+package pkg
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+)
+
+// Filter decides whether an alert is delivered. All configured conditions must hold;
+// an empty filter lets everything through.
+type Filter struct {
+	// Message type URLs, glob patterns allowed (e.g. "/ibc.core.client.*"). With
+	// include_types at least one message, or one it executes through authz, must match;
+	// messages matching exclude_types are ignored, also when executed through authz, as
+	// is a MsgExec whose messages are all excluded. A tx with nothing else left is dropped.
+	IncludeTypes []string `yaml:"include_types"`
+	ExcludeTypes []string `yaml:"exclude_types"`
+	// Minimum amount per display denom, e.g. {atom: 10}. A tx is dropped when all of
+	// its amounts are in listed denoms and below their minimum.
+	MinAmounts map[string]Decimal `yaml:"min_amounts"`
+	// Minimum fiat value of the tx's amounts together, in prices.currency, e.g. 50000.
+	// Amounts without a price count as nothing, so a tx without priced amounts is dropped.
+	MinValue *Decimal `yaml:"min_value"`
+	// Which amounts min_value counts: "any" (the default) or "outgoing", only those of
+	// messages the wallet pays from, see messagePayers.
+	MinValueDirection string `yaml:"min_value_direction"`
+	FailedOnly        bool   `yaml:"failed_only"`
+	MemoRegex         string `yaml:"memo_regex"`
+	// Addresses in the tx's messages other than the monitored wallet. With allow, at
+	// least one counterparty must be listed; any denied counterparty drops the tx.
+	Counterparties struct {
+		Allow []string `yaml:"allow"`
+		Deny  []string `yaml:"deny"`
+	} `yaml:"counterparties"`
+
+	memo *regexp.Regexp
+}
+
+func (f *Filter) compile() error {
+	if f == nil {
+		return nil
+	}
+	switch f.MinValueDirection {
+	case "", "any", "outgoing":
+	default:
+		return fmt.Errorf("invalid min_value_direction %q, expected any or outgoing", f.MinValueDirection)
+	}
+	if f.MemoRegex == "" {
+		return nil
+	}
+	memo, err := regexp.Compile(f.MemoRegex)
+	if err != nil {
+		return fmt.Errorf("invalid memo_regex %q: %v", f.MemoRegex, err)
+	}
+	f.memo = memo
+	return nil
+}
+
+// Match reports whether the alert passes the filter. A nil filter matches everything.
+func (f *Filter) Match(alertData AlertData) bool {
+	if f == nil {
+		return true
+	}
+	if f.FailedOnly && alertData.Error == "" {
+		return false
+	}
+	if f.memo != nil && !f.memo.MatchString(alertData.Memo) {
+		return false
+	}
+
+	messages := withoutExcluded(f.ExcludeTypes, alertData.MessageDetails)
+	if len(messages) == 0 && len(alertData.MessageDetails) > 0 {
+		return false
+	}
+	// The remaining checks also look at the messages executed through authz.
+	messages = flattenMessages(messages)
+	if len(f.IncludeTypes) > 0 {
+		included := false
+		for _, detail := range messages {
+			included = included || matchTypes(f.IncludeTypes, detail.Type)
+		}
+		if !included {
+			return false
+		}
+	}
+
+	if len(f.MinAmounts) > 0 {
+		hasAmount, aboveMin := false, false
+		for _, detail := range messages {
+			for _, coin := range detail.Amounts {
+				hasAmount = true
+				min, listed := f.MinAmounts[coin.Denom]
+				aboveMin = aboveMin || !listed || coin.Amount.Cmp(min) >= 0
+			}
+		}
+		if hasAmount && !aboveMin {
+			return false
+		}
+	}
+
+	if f.MinValue != nil {
+		var total Decimal
+		for _, detail := range messages {
+			if f.MinValueDirection == "outgoing" && !toSet(detail.payers)[alertData.WalletAddress] {
+				continue
+			}
+			for _, coin := range detail.Amounts {
+				if coin.Value != nil {
+					total = total.Add(coin.Value.Amount)
+				}
+			}
+		}
+		if total.Cmp(*f.MinValue) < 0 {
+			return false
+		}
+	}
+
+	if len(f.Counterparties.Allow) > 0 || len(f.Counterparties.Deny) > 0 {
+		allow, deny := toSet(f.Counterparties.Allow), toSet(f.Counterparties.Deny)
+		allowed := len(allow) == 0
+		for _, detail := range messages {
+			for _, address := range detail.Addresses {
+				if address == alertData.WalletAddress {
+					continue
+				}
+				if deny[address] {
+					return false
+				}
+				allowed = allowed || allow[address]
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+
+	return true
+}
+
+// withoutExcluded drops the messages matching patterns, and those nested in the others.
+// A message whose nested messages are all dropped, such as a MsgExec, is dropped too.
+func withoutExcluded(patterns []string, details []MessageDetail) []MessageDetail {
+	var kept []MessageDetail
+	for _, detail := range details {
+		if matchTypes(patterns, detail.Type) {
+			continue
+		}
+		if len(detail.Messages) > 0 {
+			detail.Messages = withoutExcluded(patterns, detail.Messages)
+			if len(detail.Messages) == 0 {
+				continue
+			}
+		}
+		kept = append(kept, detail)
+	}
+	return kept
+}
+
+func matchTypes(patterns []string, messageType string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, messageType); ok || pattern == messageType {
+			return true
+		}
+	}
+	return false
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
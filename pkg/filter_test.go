@@ -0,0 +1,80 @@
+package pkg
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestFilterExcludeTypesNested(t *testing.T) {
+	const (
+		exec     = "/cosmos.authz.v1beta1.MsgExec"
+		delegate = "/cosmos.staking.v1beta1.MsgDelegate"
+		withdraw = "/cosmos.distribution.v1beta1.MsgWithdrawDelegatorReward"
+		send     = "/cosmos.bank.v1beta1.MsgSend"
+	)
+	message := func(messageType string, nested ...MessageDetail) MessageDetail {
+		return MessageDetail{Type: messageType, Messages: nested}
+	}
+	tests := []struct {
+		name     string
+		filter   Filter
+		messages []MessageDetail
+		want     bool
+	}{
+		{"restake excluded", Filter{ExcludeTypes: []string{delegate, withdraw}},
+			[]MessageDetail{message(exec, message(withdraw), message(delegate))}, false},
+		{"restake partly excluded", Filter{ExcludeTypes: []string{delegate}},
+			[]MessageDetail{message(exec, message(withdraw), message(delegate))}, true},
+		{"exec excluded", Filter{ExcludeTypes: []string{exec}},
+			[]MessageDetail{message(exec, message(send))}, false},
+		{"excluded nested message does not include", Filter{IncludeTypes: []string{delegate}, ExcludeTypes: []string{"/cosmos.staking.*"}},
+			[]MessageDetail{message(exec, message(delegate)), message(send)}, false},
+		{"nested message included", Filter{IncludeTypes: []string{send}},
+			[]MessageDetail{message(exec, message(send))}, true},
+		{"top-level excluded, other kept", Filter{ExcludeTypes: []string{delegate}},
+			[]MessageDetail{message(delegate), message(send)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(AlertData{MessageDetails: tt.messages}); got != tt.want {
+				t.Errorf("Match = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterMinValueDirection(t *testing.T) {
+	worth := func(value int64, payers ...string) MessageDetail {
+		return MessageDetail{
+			Amounts: []Coin{{Denom: "atom", Value: &FiatValue{Amount: NewDecimal(big.NewInt(value), 0), Currency: "usd"}}},
+			payers:  payers,
+		}
+	}
+	min := NewDecimal(big.NewInt(100), 0)
+	tests := []struct {
+		name      string
+		direction string
+		messages  []MessageDetail
+		want      bool
+	}{
+		{"incoming counts by default", "", []MessageDetail{worth(150, "cosmos1other")}, true},
+		{"incoming ignored", "outgoing", []MessageDetail{worth(150, "cosmos1other")}, false},
+		{"reward ignored", "outgoing", []MessageDetail{worth(150)}, false},
+		{"outgoing counts", "outgoing", []MessageDetail{worth(60, "cosmos1wallet"), worth(60, "cosmos1wallet"), worth(500)}, true},
+		{"outgoing below minimum", "outgoing", []MessageDetail{worth(60, "cosmos1wallet"), worth(500)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := Filter{MinValue: &min, MinValueDirection: tt.direction}
+			if err := filter.compile(); err != nil {
+				t.Fatal(err)
+			}
+			if got := filter.Match(AlertData{WalletAddress: "cosmos1wallet", MessageDetails: tt.messages}); got != tt.want {
+				t.Errorf("Match = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if err := (&Filter{MinValueDirection: "incoming"}).compile(); err == nil {
+		t.Error("compile accepted min_value_direction incoming")
+	}
+}
@@ -0,0 +1,218 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	proposalTitleRetry = 5 * time.Minute
+	proposalSummaryMax = 300 // bytes shown of a proposal's summary or description
+)
+
+// WeightedVoteOption is one option of a MsgVoteWeighted, weight being a fraction.
+type WeightedVoteOption struct {
+	Option string `json:"option"`
+	Weight string `json:"weight"`
+}
+
+// ProposalContent is the legacy content of a gov v1beta1 proposal.
+type ProposalContent struct {
+	Type        string `json:"@type"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// voteOptionNames name the vote options, which APIs serve as enum names or numbers.
+var voteOptionNames = map[string]string{
+	"VOTE_OPTION_UNSPECIFIED":  "Unspecified",
+	"VOTE_OPTION_YES":          "Yes",
+	"VOTE_OPTION_ABSTAIN":      "Abstain",
+	"VOTE_OPTION_NO":           "No",
+	"VOTE_OPTION_NO_WITH_VETO": "No With Veto",
+	"0":                        "Unspecified",
+	"1":                        "Yes",
+	"2":                        "Abstain",
+	"3":                        "No",
+	"4":                        "No With Veto",
+}
+
+func voteOptionName(option string) string {
+	if name, ok := voteOptionNames[option]; ok {
+		return name
+	}
+	return option
+}
+
+// populateGovDetails adds the title of the proposal voted on or deposited to, weighted
+// vote options, and what a submitted proposal proposes.
+func (d messageDecoder) populateGovDetails(details *MessageDetail, message Message, msgIndex int) {
+	switch message.Type {
+	case "/cosmos.gov.v1beta1.MsgVote", "/cosmos.gov.v1.MsgVote",
+		"/cosmos.gov.v1beta1.MsgVoteWeighted", "/cosmos.gov.v1.MsgVoteWeighted",
+		"/cosmos.gov.v1beta1.MsgDeposit", "/cosmos.gov.v1.MsgDeposit":
+		if len(message.Options) > 0 {
+			var options []string
+			for _, option := range message.Options {
+				options = append(options, voteOptionName(option.Option)+" "+formatRate(option.Weight))
+			}
+			weighted := strings.Join(options, ", ")
+			appendIfNotNil(&details.Details, "Options", &weighted)
+		}
+		if message.ProposalId != nil {
+			if title, ok := proposalTitles.Get(d.chain.API, *message.ProposalId); ok {
+				appendIfNotNil(&details.Details, "Proposal Title", &title)
+			}
+		}
+	case "/cosmos.gov.v1beta1.MsgSubmitProposal", "/cosmos.gov.v1.MsgSubmitProposal":
+		if id := findEventAttribute(d.apiData, msgIndex, "submit_proposal", "proposal_id"); id != "" {
+			appendIfNotNil(&details.Details, "Proposal Id", &id)
+		}
+		title, summary := message.Title, message.Summary
+		if content := message.Content; content != nil {
+			title, summary = &content.Title, &content.Description
+			appendIfNotNil(&details.Details, "Proposal Type", &content.Type)
+		}
+		if title != nil && *title != "" {
+			appendIfNotNil(&details.Details, "Proposal Title", title)
+		}
+		if summary != nil && *summary != "" {
+			text := truncate(*summary, proposalSummaryMax)
+			appendIfNotNil(&details.Details, "Summary", &text)
+		}
+		if len(message.ProposalMessages) > 0 {
+			var types []string
+			for _, inner := range message.ProposalMessages {
+				types = append(types, inner.Type)
+			}
+			proposed := strings.Join(types, ", ")
+			appendIfNotNil(&details.Details, "Proposal Messages", &proposed)
+		}
+		if message.Expedited {
+			expedited := "yes"
+			appendIfNotNil(&details.Details, "Expedited", &expedited)
+		}
+		if deposit := d.prices.value(d.chain.displayCoins(message.InitialDeposit)); len(deposit) > 0 {
+			text := formatCoins(deposit)
+			appendIfNotNil(&details.Details, "Initial Deposit", &text)
+			details.Amounts = append(details.Amounts, deposit...)
+		}
+	default:
+		return
+	}
+
+	for _, address := range []*string{message.Proposer, message.Depositor} {
+		if address != nil && *address != "" {
+			details.Addresses = append(details.Addresses, *address)
+		}
+	}
+}
+
+// proposalTitleCache keeps proposal titles by API and id. A proposal's title does not
+// change, so titles are kept for good.
+type proposalTitleCache struct {
+	mu      sync.Mutex
+	client  *http.Client
+	titles  map[string]string
+	failed  map[string]time.Time // proposals whose lookup failed, retried after proposalTitleRetry
+	flights singleflight.Group   // lookups in progress, run outside the lock
+}
+
+var proposalTitles = &proposalTitleCache{
+	client: &http.Client{Timeout: 15 * time.Second},
+	titles: make(map[string]string),
+	failed: make(map[string]time.Time),
+}
+
+// Get returns the title of a proposal, asking the gov module at api when it is not cached.
+func (c *proposalTitleCache) Get(api string, id string) (string, bool) {
+	if api == "" || id == "" {
+		return "", false
+	}
+	key := api + "#" + id
+	c.mu.Lock()
+	title, ok := c.titles[key]
+	failedAt := c.failed[key]
+	c.mu.Unlock()
+
+	if ok {
+		return title, true
+	}
+	if time.Since(failedAt) < proposalTitleRetry {
+		return "", false
+	}
+	fetched, err, _ := c.flights.Do(key, func() (interface{}, error) {
+		title, err := c.fetch(api, id)
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if err != nil {
+			log.Printf("Error fetching proposal %s on %s: %v", id, api, err)
+			c.failed[key] = time.Now()
+			return "", err
+		}
+		delete(c.failed, key)
+		c.titles[key] = title
+		return title, nil
+	})
+	if err != nil {
+		return "", false
+	}
+	return fetched.(string), true
+}
+
+// fetch asks gov v1 for the proposal, whose title is its own from SDK v0.47 on and that
+// of its legacy content before, then gov v1beta1 on chains without v1.
+func (c *proposalTitleCache) fetch(api string, id string) (string, error) {
+	var v1 struct {
+		Proposal struct {
+			Title    string `json:"title"`
+			Messages []struct {
+				Content *ProposalContent `json:"content"`
+			} `json:"messages"`
+		} `json:"proposal"`
+	}
+	err := c.getJSON(fmt.Sprintf("%s/cosmos/gov/v1/proposals/%s", api, id), &v1)
+	if err == nil {
+		if v1.Proposal.Title != "" {
+			return v1.Proposal.Title, nil
+		}
+		for _, message := range v1.Proposal.Messages {
+			if message.Content != nil && message.Content.Title != "" {
+				return message.Content.Title, nil
+			}
+		}
+	}
+
+	var v1beta1 struct {
+		Proposal struct {
+			Content ProposalContent `json:"content"`
+		} `json:"proposal"`
+	}
+	if err := c.getJSON(fmt.Sprintf("%s/cosmos/gov/v1beta1/proposals/%s", api, id), &v1beta1); err != nil {
+		return "", err
+	}
+	if v1beta1.Proposal.Content.Title == "" {
+		return "", fmt.Errorf("proposal has no title")
+	}
+	return v1beta1.Proposal.Content.Title, nil
+}
+
+func (c *proposalTitleCache) getJSON(url string, value interface{}) error {
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("non-200 status code: %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(value)
+}
@@ -0,0 +1,52 @@
+package pkg
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestProposalTitleCacheFetchesOutsideLock(t *testing.T) {
+	release := make(chan struct{})
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		if id == "1" {
+			<-release
+		}
+		fmt.Fprintf(w, `{"proposal":{"title":"Proposal %s"}}`, id)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	cache := &proposalTitleCache{client: server.Client(), titles: make(map[string]string), failed: make(map[string]time.Time)}
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache.Get(server.URL, "1")
+		}()
+	}
+	waitFor(t, 5*time.Second, func() bool { return requests.Load() == 1 })
+
+	// A slow proposal does not hold up others.
+	if title, ok := cache.Get(server.URL, "2"); !ok || title != "Proposal 2" {
+		t.Fatalf("Get(2) = %q, %v", title, ok)
+	}
+
+	release <- struct{}{}
+	wg.Wait()
+	if title, ok := cache.Get(server.URL, "1"); !ok || title != "Proposal 1" {
+		t.Errorf("Get(1) = %q, %v", title, ok)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("%d requests, want one per proposal", n)
+	}
+}
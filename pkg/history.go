@@ -0,0 +1,63 @@
+package pkg
+
+import (
+	"sync"
+	"time"
+)
+
+const historySize = 50
+
+const (
+	AlertDelivered = "delivered"
+	AlertQueued    = "queued" // held back by the rate limit of a notifier
+	AlertMuted     = "muted"
+	AlertFiltered  = "filtered"
+)
+
+// AlertRecord is a processed alert together with what happened to it.
+type AlertRecord struct {
+	Alert      AlertData `json:"alert"`
+	Status     string    `json:"status"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// alertHistory keeps the most recent alerts of every chain in memory.
+type alertHistory struct {
+	mu      sync.Mutex
+	size    int
+	records map[string][]AlertRecord
+}
+
+var history = newAlertHistory(historySize)
+
+func newAlertHistory(size int) *alertHistory {
+	return &alertHistory{size: size, records: make(map[string][]AlertRecord)}
+}
+
+func (h *alertHistory) Add(alertData AlertData, status string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	records := append(h.records[alertData.ChainName], AlertRecord{
+		Alert:      alertData,
+		Status:     status,
+		ReceivedAt: time.Now(),
+	})
+	if len(records) > h.size {
+		records = records[len(records)-h.size:]
+	}
+	h.records[alertData.ChainName] = records
+}
+
+// Last returns up to n records of a chain, newest first.
+func (h *alertHistory) Last(chainName string, n int) []AlertRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	records := h.records[chainName]
+	var last []AlertRecord
+	for i := len(records) - 1; i >= 0 && len(last) < n; i-- {
+		last = append(last, records[i])
+	}
+	return last
+}
@@ -0,0 +1,125 @@
+package pkg
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	IBCPacketSend            = "send"
+	IBCPacketAcknowledgement = "acknowledgement"
+	IBCPacketTimeout         = "timeout"
+)
+
+// IBCPacket identifies a packet sent or settled by a transaction, so that the
+// acknowledgement or timeout of an earlier MsgTransfer can be matched to it.
+type IBCPacket struct {
+	Kind          string `json:"kind"`
+	SourceChannel string `json:"source_channel"`
+	Sequence      string `json:"sequence"`
+	Error         string `json:"error,omitempty"`
+}
+
+// Key is unique per packet on the source chain.
+func (p IBCPacket) Key(chainName string) string {
+	return fmt.Sprintf("%s/%s/%s", chainName, p.SourceChannel, p.Sequence)
+}
+
+func extractIBCPackets(apiData *Response) []IBCPacket {
+	var packets []IBCPacket
+	for i, message := range apiData.Tx.Body.Messages {
+		switch message.Type {
+		case "/ibc.applications.transfer.v1.MsgTransfer":
+			sequence := findEventAttribute(apiData, i, "send_packet", "packet_sequence")
+			channel := findEventAttribute(apiData, i, "send_packet", "packet_src_channel")
+			if sequence != "" && channel != "" {
+				packets = append(packets, IBCPacket{Kind: IBCPacketSend, SourceChannel: channel, Sequence: sequence})
+			}
+		case "/ibc.core.channel.v1.MsgAcknowledgement":
+			if packet := message.Packet; packet != nil && packet.SourceChannel != nil && packet.PacketSequence != nil {
+				packets = append(packets, IBCPacket{
+					Kind:          IBCPacketAcknowledgement,
+					SourceChannel: *packet.SourceChannel,
+					Sequence:      *packet.PacketSequence,
+					Error:         findEventAttribute(apiData, i, "fungible_token_packet", "error"),
+				})
+			}
+		case "/ibc.core.channel.v1.MsgTimeout", "/ibc.core.channel.v1.MsgTimeoutOnClose":
+			if packet := message.Packet; packet != nil && packet.SourceChannel != nil && packet.PacketSequence != nil {
+				packets = append(packets, IBCPacket{Kind: IBCPacketTimeout, SourceChannel: *packet.SourceChannel, Sequence: *packet.PacketSequence})
+			}
+		}
+	}
+	return packets
+}
+
+// findEventAttribute looks up an event attribute emitted by the message at msgIndex.
+// Older SDKs group events per message in logs; newer ones tag events with msg_index.
+func findEventAttribute(apiData *Response, msgIndex int, eventType string, key string) string {
+	if values := findEventAttributes(apiData, msgIndex, eventType, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// findEventAttributes returns every value of an attribute of the events of a type
+// emitted by the message at msgIndex, in order, e.g. one withdraw_rewards amount per
+// message of an authz MsgExec. Logs of SDK 0.47 and older merge the events of a type
+// into one event with repeated attributes, so all of an event's values are taken.
+func findEventAttributes(apiData *Response, msgIndex int, eventType string, key string) []string {
+	var values []string
+	for _, event := range findEvents(apiData, msgIndex, eventType) {
+		for _, attr := range event.Attributes {
+			if attr.Key == key {
+				values = append(values, attr.Value)
+			}
+		}
+	}
+	return values
+}
+
+// findEvents returns the events of a type emitted by the message at msgIndex.
+func findEvents(apiData *Response, msgIndex int, eventType string) []Event {
+	if len(apiData.TxResponse.Logs) > 0 {
+		for _, log := range apiData.TxResponse.Logs {
+			if log.MsgIndex == int64(msgIndex) {
+				return eventsOfType(log.Events, eventType, "")
+			}
+		}
+		return nil
+	}
+	return eventsOfType(apiData.TxResponse.Events, eventType, strconv.Itoa(msgIndex))
+}
+
+// eventsOfType returns the events of a type tagged with msgIndex, or all of them when
+// msgIndex is "". Untagged events only count when no event of the tx is tagged: on SDK
+// 0.50 and newer those are tx-level events, such as the fee transfer of the ante handler.
+func eventsOfType(events []Event, eventType string, msgIndex string) []Event {
+	tagged := false
+	for _, event := range events {
+		if eventMsgIndex(event) != "" {
+			tagged = true
+			break
+		}
+	}
+	var matched []Event
+	for _, event := range events {
+		if event.Type != eventType {
+			continue
+		}
+		index := eventMsgIndex(event)
+		if msgIndex == "" || index == msgIndex || !tagged && index == "" {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+func eventMsgIndex(event Event) string {
+	for _, attr := range event.Attributes {
+		if attr.Key == "msg_index" {
+			return attr.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,195 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configFile is one YAML file of the config with its top-level mapping.
+type configFile struct {
+	path    string
+	root    *yaml.Node
+	modTime time.Time
+	size    int64
+}
+
+// readConfigFiles returns the files making up the config at path: the *.yml and *.yaml
+// files of a directory in name order, or a single file, each followed by the files its
+// "include" globs match (relative to the including file). Problems in the files are
+// recorded in v; err is only set when path itself cannot be read.
+func (v *validator) readConfigFiles(path string) ([]configFile, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		paths = nil
+		for _, entry := range entries {
+			name := entry.Name()
+			if !entry.IsDir() && !strings.HasPrefix(name, ".") && (strings.HasSuffix(name, ".yml") || strings.HasSuffix(name, ".yaml")) {
+				paths = append(paths, filepath.Join(path, name))
+			}
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("no .yml or .yaml files in %s", path)
+		}
+	}
+
+	var files []configFile
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		files = v.readConfigFile(path, seen, files)
+	}
+	return files, nil
+}
+
+func (v *validator) readConfigFile(path string, seen map[string]bool, files []configFile) []configFile {
+	if abs, err := filepath.Abs(path); err == nil {
+		if seen[abs] {
+			return files
+		}
+		seen[abs] = true
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		v.nodef(nil, nil, "%v", err)
+		return files
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		v.nodef(nil, nil, "%v", err)
+		return files
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		problem := yamlProblem(err.Error())
+		problem.File = path
+		v.problems = append(v.problems, problem)
+		return files
+	}
+	if len(document.Content) == 0 {
+		return files // empty file
+	}
+	root := document.Content[0]
+	markFile(root, path, v.files)
+	if root.Kind != yaml.MappingNode {
+		v.nodef(root, nil, "expected a mapping at the top level")
+		return files
+	}
+	files = append(files, configFile{path: path, root: root, modTime: info.ModTime(), size: info.Size()})
+
+	// Included files follow their includer so that its settings are merged first.
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "include" {
+			continue
+		}
+		value := root.Content[i+1]
+		root.Content = append(root.Content[:i], root.Content[i+2:]...)
+
+		patterns := []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			patterns = value.Content
+		}
+		for _, pattern := range patterns {
+			if pattern.Kind != yaml.ScalarNode {
+				v.nodef(pattern, at("include"), "expected a file name or glob")
+				continue
+			}
+			glob := pattern.Value
+			if !filepath.IsAbs(glob) {
+				glob = filepath.Join(filepath.Dir(path), glob)
+			}
+			matches, err := filepath.Glob(glob)
+			if err != nil {
+				v.nodef(pattern, at("include"), "%v", err)
+				continue
+			}
+			if len(matches) == 0 && !strings.ContainsAny(pattern.Value, "*?[") {
+				v.nodef(pattern, at("include"), "%s does not exist", glob)
+			}
+			sort.Strings(matches)
+			for _, match := range matches {
+				files = v.readConfigFile(match, seen, files)
+			}
+		}
+		break
+	}
+	return files
+}
+
+func markFile(node *yaml.Node, path string, files map[*yaml.Node]string) {
+	files[node] = path
+	for _, child := range node.Content {
+		markFile(child, path, files)
+	}
+}
+
+// mergeConfigFiles merges the mappings of all files in order. Nested mappings
+// such as chains and alerting are merged key by key; any other value set in two files
+// is a conflict.
+func (v *validator) mergeConfigFiles(files []configFile) *yaml.Node {
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, file := range files {
+		v.mergeMapping(merged, file.root, nil)
+	}
+	return merged
+}
+
+func (v *validator) mergeMapping(dst *yaml.Node, src *yaml.Node, path []interface{}) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		existing := mappingValue(dst, key.Value)
+		switch {
+		case existing == nil || isNull(existing):
+			setMappingValue(dst, key, value)
+		case isNull(value):
+		case existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			v.mergeMapping(existing, value, append(path[:len(path):len(path)], key.Value))
+		default:
+			v.nodef(key, append(path[:len(path):len(path)], key.Value), "already set in %s:%d", v.files[existing], existing.Line)
+		}
+	}
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+func setMappingValue(node *yaml.Node, key *yaml.Node, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key.Value {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	node.Content = append(node.Content, key, value)
+}
+
+// configVersion changes whenever a file of the config at path is changed, added or removed.
+func configVersion(path string) string {
+	v := &validator{files: make(map[*yaml.Node]string)}
+	files, err := v.readConfigFiles(path)
+	if err != nil {
+		return ""
+	}
+	var version strings.Builder
+	for _, file := range files {
+		fmt.Fprintf(&version, "%s %d %d\n", file.path, file.modTime.UnixNano(), file.size)
+	}
+	for _, problem := range v.problems {
+		version.WriteString(problem.String() + "\n")
+	}
+	return version.String()
+}
@@ -0,0 +1,39 @@
+package pkg
+
+import (
+	"context"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// NewKafkaPublisher writes events keyed by chain/wallet, waiting for all in-sync replicas.
+func NewKafkaPublisher(brokers []string, topic string, queueDir string) (Notifier, error) {
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		MaxAttempts:  1,
+	}
+
+	p, err := newPublisher("Kafka", queueDir, func(event TxEvent, payload []byte) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		return writer.WriteMessages(ctx, kafka.Message{
+			Key:   []byte(event.Key),
+			Value: payload,
+			Headers: []kafka.Header{
+				{Key: "chain", Value: []byte(event.ChainName)},
+				{Key: "wallet", Value: []byte(event.WalletAddress)},
+				{Key: "tx_hash", Value: []byte(event.TxHash)},
+			},
+		})
+	}, writer.Close)
+	if err != nil {
+		writer.Close()
+		return nil, err
+	}
+	return p, nil
+}
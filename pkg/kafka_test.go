@@ -0,0 +1,155 @@
+package pkg
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go/protocol"
+	"github.com/segmentio/kafka-go/protocol/apiversions"
+	"github.com/segmentio/kafka-go/protocol/metadata"
+	"github.com/segmentio/kafka-go/protocol/produce"
+)
+
+// fakeKafkaBroker is a single-node broker with one partition per topic that answers
+// just what a kafka.Writer asks: API versions, metadata and produce requests.
+type fakeKafkaBroker struct {
+	listener net.Listener
+	topic    string
+
+	mu      sync.Mutex
+	records []kafkaTestRecord
+}
+
+type kafkaTestRecord struct {
+	key, value []byte
+	headers    map[string]string
+}
+
+func runFakeKafkaBroker(t *testing.T, topic string) *fakeKafkaBroker {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	b := &fakeKafkaBroker{listener: listener, topic: topic}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(conn)
+		}
+	}()
+	return b
+}
+
+func (b *fakeKafkaBroker) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	host, portText, _ := net.SplitHostPort(b.listener.Addr().String())
+	port, _ := strconv.Atoi(portText)
+
+	for {
+		apiVersion, correlationID, _, msg, err := protocol.ReadRequest(reader)
+		if err != nil {
+			return
+		}
+		var response protocol.Message
+		switch request := msg.(type) {
+		case *apiversions.Request:
+			response = &apiversions.Response{ApiKeys: []apiversions.ApiKeyResponse{
+				{ApiKey: int16(protocol.ApiVersions), MinVersion: 0, MaxVersion: 2},
+				{ApiKey: int16(protocol.Metadata), MinVersion: 0, MaxVersion: 8},
+				{ApiKey: int16(protocol.Produce), MinVersion: 0, MaxVersion: 8},
+			}}
+		case *metadata.Request:
+			response = &metadata.Response{
+				Brokers:      []metadata.ResponseBroker{{NodeID: 1, Host: host, Port: int32(port)}},
+				ControllerID: 1,
+				Topics: []metadata.ResponseTopic{{Name: b.topic, Partitions: []metadata.ResponsePartition{
+					{PartitionIndex: 0, LeaderID: 1, ReplicaNodes: []int32{1}, IsrNodes: []int32{1}},
+				}}},
+			}
+		case *produce.Request:
+			produced := &produce.Response{}
+			for _, topic := range request.Topics {
+				responseTopic := produce.ResponseTopic{Topic: topic.Topic}
+				for _, partition := range topic.Partitions {
+					b.store(partition.RecordSet)
+					responseTopic.Partitions = append(responseTopic.Partitions, produce.ResponsePartition{Partition: partition.Partition})
+				}
+				produced.Topics = append(produced.Topics, responseTopic)
+			}
+			response = produced
+		default:
+			return
+		}
+		if err := protocol.WriteResponse(conn, apiVersion, correlationID, response); err != nil {
+			return
+		}
+	}
+}
+
+func (b *fakeKafkaBroker) store(recordSet protocol.RecordSet) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for {
+		record, err := recordSet.Records.ReadRecord()
+		if err != nil { // io.EOF after the last record
+			return
+		}
+		key, _ := protocol.ReadAll(record.Key)
+		value, _ := protocol.ReadAll(record.Value)
+		headers := make(map[string]string)
+		for _, header := range record.Headers {
+			headers[header.Key] = string(header.Value)
+		}
+		b.records = append(b.records, kafkaTestRecord{key: key, value: value, headers: headers})
+	}
+}
+
+func (b *fakeKafkaBroker) received() []kafkaTestRecord {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]kafkaTestRecord(nil), b.records...)
+}
+
+func TestKafkaPublisher(t *testing.T) {
+	broker := runFakeKafkaBroker(t, "txs")
+	notifier, err := NewKafkaPublisher([]string{broker.listener.Addr().String()}, "txs", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeNotifier(notifier)
+
+	if err := notifier.Notify(AlertData{ChainName: "kava", WalletAddress: "kava1abc", TxHash: "AB12"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var records []kafkaTestRecord
+	waitFor(t, 10*time.Second, func() bool {
+		records = broker.received()
+		return len(records) == 1
+	})
+	record := records[0]
+	if string(record.key) != "kava/kava1abc" {
+		t.Errorf("key = %q", record.key)
+	}
+	if record.headers["tx_hash"] != "AB12" || record.headers["chain"] != "kava" || record.headers["wallet"] != "kava1abc" {
+		t.Errorf("headers = %v", record.headers)
+	}
+	var event TxEvent
+	if err := json.Unmarshal(record.value, &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.TxHash != "AB12" || event.Alert.ChainName != "kava" {
+		t.Errorf("event = %+v", event)
+	}
+}
@@ -0,0 +1,85 @@
+package pkg
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Matrix rejects events larger than 65536 bytes; keep headroom for the envelope.
+const matrixMaxBodySize = 30000
+
+type MatrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format,omitempty"`
+	FormattedBody string `json:"formatted_body,omitempty"`
+}
+
+func SendMatrixMessage(homeserver string, accessToken string, roomID string, alertData AlertData) error {
+	txnID := fmt.Sprintf("%s-%d", alertData.TxHash, time.Now().UnixNano())
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		strings.TrimSuffix(homeserver, "/"), url.PathEscape(roomID), url.PathEscape(txnID))
+
+	explorer := alertData.TxURL()
+
+	var plain, formatted strings.Builder
+	plain.WriteString(fmt.Sprintf("%s\n%s\n", alertData.heading(), explorer))
+	formatted.WriteString(fmt.Sprintf("<h4>%s</h4><a href=\"%s\">View on Explorer</a><br/>",
+		html.EscapeString(alertData.heading()), html.EscapeString(explorer)))
+	if alertData.Error != "" {
+		plain.WriteString(fmt.Sprintf("Error: %s\n", alertData.Error))
+		formatted.WriteString(fmt.Sprintf("<b>Error:</b> <pre><code>%s</code></pre>", html.EscapeString(alertData.Error)))
+	}
+	plain.WriteString(fmt.Sprintf("Transaction: %s\nHeight: %s\nFees: %s\nMemo: %s\n", alertData.TxHash, alertData.Height, alertData.Fees, alertData.Memo))
+	formatted.WriteString(fmt.Sprintf("<b>Transaction:</b> <code>%s</code><br/><b>Height:</b> <code>%s</code><br/><b>Fees:</b> <code>%s</code><br/><b>Memo:</b> <code>%s</code>",
+		html.EscapeString(alertData.TxHash), html.EscapeString(alertData.Height), html.EscapeString(alertData.Fees), html.EscapeString(alertData.Memo)))
+	if paidBy := alertData.feePaidBy(); paidBy != "" {
+		plain.WriteString(fmt.Sprintf("Fee Paid By: %s\n", paidBy))
+		formatted.WriteString(fmt.Sprintf("<br/><b>Fee Paid By:</b> <code>%s</code>", html.EscapeString(paidBy)))
+	}
+
+	for _, detail := range alertData.allMessages() {
+		plain.WriteString(fmt.Sprintf("\n%s\n", detail.Title()))
+		formatted.WriteString(fmt.Sprintf("<h5>%s</h5><ul>", html.EscapeString(detail.Title())))
+		for _, d := range detail.Details {
+			for k, v := range d {
+				plain.WriteString(fmt.Sprintf("%s: %s\n", k, v))
+				formatted.WriteString(fmt.Sprintf("<li><b>%s:</b> <code>%s</code></li>", html.EscapeString(k), html.EscapeString(v)))
+			}
+		}
+		formatted.WriteString("</ul>")
+	}
+
+	message := MatrixMessage{
+		MsgType: "m.text",
+		Body:    truncate(plain.String(), matrixMaxBodySize),
+		Format:  "org.matrix.custom.html",
+	}
+	// A truncated HTML body could leave tags unbalanced, so fall back to plain text.
+	if formattedBody := formatted.String(); len(formattedBody) <= matrixMaxBodySize {
+		message.FormattedBody = formattedBody
+	} else {
+		message.Format = ""
+	}
+
+	return postJSON("PUT", endpoint, message, map[string]string{
+		"Authorization": "Bearer " + accessToken,
+	})
+}
+
+func SendMatrixSummary(homeserver string, accessToken string, roomID string, summary Summary) error {
+	txnID := fmt.Sprintf("summary-%d", time.Now().UnixNano())
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		strings.TrimSuffix(homeserver, "/"), url.PathEscape(roomID), url.PathEscape(txnID))
+
+	message := MatrixMessage{
+		MsgType: "m.text",
+		Body:    truncate(summary.Text("", plainLink), matrixMaxBodySize),
+	}
+	return postJSON("PUT", endpoint, message, map[string]string{
+		"Authorization": "Bearer " + accessToken,
+	})
+}
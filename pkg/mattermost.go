@@ -0,0 +1,58 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mattermost's default MaxPostSize is 16383 runes.
+const mattermostMaxPostSize = 16000
+
+type MattermostWebhook struct {
+	Text     string `json:"text"`
+	Channel  string `json:"channel,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+func SendMattermostWebhook(webhookURL string, channel string, alertData AlertData) error {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("#### %s\n[View on Explorer](%s)\n", alertData.heading(), alertData.TxURL()))
+	if alertData.Error != "" {
+		text.WriteString(fmt.Sprintf("**Error:** `%s`\n", alertData.Error))
+	}
+	text.WriteString(fmt.Sprintf("**Transaction:** `%s`\n", alertData.TxHash))
+	text.WriteString(fmt.Sprintf("**Height:** `%s`\n**Fees:** `%s`\n**Memo:** `%s`\n", alertData.Height, alertData.Fees, alertData.Memo))
+	if paidBy := alertData.feePaidBy(); paidBy != "" {
+		text.WriteString(fmt.Sprintf("**Fee Paid By:** `%s`\n", paidBy))
+	}
+
+	for _, detail := range alertData.allMessages() {
+		text.WriteString(fmt.Sprintf("\n##### %s\n", detail.Title()))
+		if len(detail.Details) == 0 {
+			continue
+		}
+		text.WriteString("| Field | Value |\n|:--|:--|\n")
+		for _, d := range detail.Details {
+			for k, v := range d {
+				text.WriteString(fmt.Sprintf("| %s | `%s` |\n", k, strings.ReplaceAll(v, "|", "\\|")))
+			}
+		}
+	}
+
+	webhook := MattermostWebhook{
+		Text:     truncate(text.String(), mattermostMaxPostSize),
+		Channel:  channel,
+		Username: "Transaction Bot",
+	}
+
+	return postJSON("POST", webhookURL, webhook, nil)
+}
+
+func SendMattermostSummary(webhookURL string, channel string, summary Summary) error {
+	webhook := MattermostWebhook{
+		Text:     truncate(summary.Text("**", markdownLink), mattermostMaxPostSize),
+		Channel:  channel,
+		Username: "Transaction Bot",
+	}
+	return postJSON("POST", webhookURL, webhook, nil)
+}
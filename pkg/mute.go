@@ -0,0 +1,228 @@
+package pkg
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MuteWindow silences alerts of the matching chains, wallets and routes (an empty
+// list matches all) either once, between Start and End, or every time Cron fires
+// for Duration. Times are UTC.
+type MuteWindow struct {
+	ID       string   `yaml:"id" json:"id"`
+	Reason   string   `yaml:"reason" json:"reason,omitempty"`
+	Chains   []string `yaml:"chains" json:"chains,omitempty"`
+	Wallets  []string `yaml:"wallets" json:"wallets,omitempty"`
+	Routes   []string `yaml:"routes" json:"routes,omitempty"`
+	Start    string   `yaml:"start" json:"start,omitempty"` // RFC3339
+	End      string   `yaml:"end" json:"end,omitempty"`     // RFC3339
+	Cron     string   `yaml:"cron" json:"cron,omitempty"`   // e.g. "0 2 * * SUN"
+	Duration string   `yaml:"duration" json:"duration,omitempty"`
+
+	start    time.Time
+	end      time.Time
+	schedule *cronSchedule
+	duration time.Duration
+}
+
+// compile parses the window. A window with only a duration starts now, which is
+// meant for runtime windows; the config requires a start for those.
+func (w *MuteWindow) compile() error {
+	var err error
+	if w.Duration != "" {
+		if w.duration, err = time.ParseDuration(w.Duration); err != nil || w.duration <= 0 {
+			return fmt.Errorf("invalid duration %q", w.Duration)
+		}
+	}
+	if w.Cron != "" {
+		if w.duration == 0 {
+			return fmt.Errorf("cron mute window needs a duration")
+		}
+		w.schedule, err = parseCron(w.Cron)
+		return err
+	}
+
+	if w.Start != "" {
+		if w.start, err = time.Parse(time.RFC3339, w.Start); err != nil {
+			return fmt.Errorf("invalid start %q", w.Start)
+		}
+	} else {
+		w.start = time.Now()
+	}
+	switch {
+	case w.End != "":
+		if w.end, err = time.Parse(time.RFC3339, w.End); err != nil {
+			return fmt.Errorf("invalid end %q", w.End)
+		}
+	case w.duration > 0:
+		w.end = w.start.Add(w.duration)
+		w.End = w.end.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Errorf("mute window needs an end, a duration or a cron schedule")
+	}
+	w.Start = w.start.UTC().Format(time.RFC3339)
+	return nil
+}
+
+// ActiveUntil reports whether the window is open at now and when it closes.
+func (w *MuteWindow) ActiveUntil(now time.Time) (time.Time, bool) {
+	if w.schedule != nil {
+		fire, ok := w.schedule.LastWithin(now.UTC(), w.duration)
+		return fire.Add(w.duration), ok
+	}
+	return w.end, !now.Before(w.start) && now.Before(w.end)
+}
+
+// expired reports whether a one-off window is over for good.
+func (w *MuteWindow) expired(now time.Time) bool {
+	return w.schedule == nil && !now.Before(w.end)
+}
+
+// matches checks the window's scope. A route of "" is the check made before routing,
+// which only windows without routes apply to.
+func (w *MuteWindow) matches(chainName string, walletAddress string, routeName string) bool {
+	if len(w.Chains) > 0 && !containsFold(w.Chains, chainName) {
+		return false
+	}
+	if len(w.Wallets) > 0 && !toSet(w.Wallets)[walletAddress] {
+		return false
+	}
+	if len(w.Routes) > 0 {
+		return routeName != "" && toSet(w.Routes)[routeName]
+	}
+	return true
+}
+
+// muteList holds the windows from the config and those added at runtime through the
+// bots or the admin API.
+type muteList struct {
+	mu      sync.Mutex
+	windows []*MuteWindow
+	runtime map[string]bool // IDs of windows added at runtime
+}
+
+var mutes = &muteList{runtime: make(map[string]bool)}
+
+// SetConfigured replaces the windows that come from the config.
+func (m *muteList) SetConfigured(windows []MuteWindow) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kept := m.windows[:0]
+	for _, window := range m.windows {
+		if m.runtime[window.ID] {
+			kept = append(kept, window)
+		}
+	}
+	m.windows = kept
+	for i := range windows {
+		window := windows[i]
+		if window.ID == "" {
+			window.ID = fmt.Sprintf("config-%d", i+1)
+		}
+		m.windows = append(m.windows, &window)
+	}
+}
+
+// Add registers a compiled runtime window and assigns it an ID.
+func (m *muteList) Add(window MuteWindow) *MuteWindow {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := make([]byte, 4)
+	rand.Read(id)
+	window.ID = hex.EncodeToString(id)
+	m.windows = append(m.windows, &window)
+	m.runtime[window.ID] = true
+	return &window
+}
+
+// Remove deletes a runtime window; configured windows cannot be removed.
+func (m *muteList) Remove(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.runtime[id] {
+		return false
+	}
+	delete(m.runtime, id)
+	for i, window := range m.windows {
+		if window.ID == id {
+			m.windows = append(m.windows[:i], m.windows[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func (m *muteList) Mute(walletAddress string, until time.Time, reason string) *MuteWindow {
+	now := time.Now()
+	window := MuteWindow{
+		Reason:  reason,
+		Wallets: []string{walletAddress},
+		Start:   now.UTC().Format(time.RFC3339),
+		End:     until.UTC().Format(time.RFC3339),
+		start:   now,
+		end:     until,
+	}
+	return m.Add(window)
+}
+
+// Unmute removes the runtime windows that mute only this wallet.
+func (m *muteList) Unmute(walletAddress string) bool {
+	var ids []string
+	m.mu.Lock()
+	for _, window := range m.windows {
+		if m.runtime[window.ID] && len(window.Wallets) == 1 && window.Wallets[0] == walletAddress &&
+			len(window.Chains) == 0 && len(window.Routes) == 0 {
+			ids = append(ids, window.ID)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, id := range ids {
+		m.Remove(id)
+	}
+	return len(ids) > 0
+}
+
+// Match returns the open window muting the alert scope, if any.
+func (m *muteList) Match(chainName string, walletAddress string, routeName string) (*MuteWindow, time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	kept := m.windows[:0]
+	var match *MuteWindow
+	var until time.Time
+	for _, window := range m.windows {
+		if m.runtime[window.ID] && window.expired(now) {
+			delete(m.runtime, window.ID)
+			continue
+		}
+		kept = append(kept, window)
+		if match != nil || !window.matches(chainName, walletAddress, routeName) {
+			continue
+		}
+		if end, active := window.ActiveUntil(now); active {
+			match, until = window, end
+		}
+	}
+	m.windows = kept
+	return match, until
+}
+
+// List returns a copy of all windows.
+func (m *muteList) List() []MuteWindow {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	windows := make([]MuteWindow, 0, len(m.windows))
+	for _, window := range m.windows {
+		windows = append(windows, *window)
+	}
+	return windows
+}
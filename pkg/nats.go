@@ -0,0 +1,53 @@
+package pkg
+
+import (
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NewNatsPublisher publishes events to "<subject>.<chain>.<wallet>". With JetStream
+// enabled every publish waits for the stream ack and is de-duplicated by tx hash and wallet.
+func NewNatsPublisher(url string, subject string, jetStream bool, queueDir string) (Notifier, error) {
+	nc, err := nats.Connect(url,
+		nats.Name("transaction-monitor"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var js nats.JetStreamContext
+	if jetStream {
+		if js, err = nc.JetStream(); err != nil {
+			nc.Close()
+			return nil, err
+		}
+	}
+
+	p, err := newPublisher("NATS", queueDir, func(event TxEvent, payload []byte) error {
+		subj := strings.Join([]string{subject, natsToken(event.ChainName), natsToken(event.WalletAddress)}, ".")
+		if js != nil {
+			_, err := js.Publish(subj, payload, nats.MsgId(event.TxHash+":"+event.WalletAddress))
+			return err
+		}
+		if err := nc.Publish(subj, payload); err != nil {
+			return err
+		}
+		return nc.FlushTimeout(5 * time.Second)
+	}, func() error {
+		return nc.Drain()
+	})
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// natsToken makes a value safe to use as a single subject token.
+func natsToken(s string) string {
+	return strings.NewReplacer(".", "_", " ", "_", "*", "_", ">", "_").Replace(s)
+}
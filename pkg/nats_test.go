@@ -0,0 +1,207 @@
+package pkg
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNatsServer speaks just enough of the NATS client protocol for a publisher: it
+// records published messages and acknowledges JetStream publishes, de-duplicating them
+// by Nats-Msg-Id like a stream does.
+type fakeNatsServer struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	messages []natsTestMessage
+	msgIDs   map[string]bool
+}
+
+type natsTestMessage struct {
+	subject string
+	msgID   string
+	data    []byte
+}
+
+func runFakeNatsServer(t *testing.T) *fakeNatsServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	s := &fakeNatsServer{listener: listener, msgIDs: make(map[string]bool)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *fakeNatsServer) URL() string {
+	return "nats://" + s.listener.Addr().String()
+}
+
+func (s *fakeNatsServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	var writeMu sync.Mutex
+	write := func(format string, args ...interface{}) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		fmt.Fprintf(conn, format, args...)
+	}
+	write("INFO %s\r\n", `{"server_id":"fake","version":"2.10.0","proto":1,"headers":true,"max_payload":1048576}`)
+
+	subscriptions := make(map[string]string) // sid -> subject
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "PING":
+			write("PONG\r\n")
+		case "SUB":
+			subscriptions[fields[len(fields)-1]] = fields[1]
+		case "UNSUB":
+			delete(subscriptions, fields[1])
+		case "PUB", "HPUB":
+			// PUB <subject> [reply] <size>, HPUB <subject> [reply] <header size> <size>
+			args, headerSize, reply := 3, 0, ""
+			if fields[0] == "HPUB" {
+				args++
+				headerSize, _ = strconv.Atoi(fields[len(fields)-2])
+			}
+			if len(fields) > args {
+				reply = fields[2]
+			}
+			size, _ := strconv.Atoi(fields[len(fields)-1])
+			body := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, body); err != nil {
+				return
+			}
+			headers, data := string(body[:headerSize]), body[headerSize:size]
+			ack := s.store(fields[1], natsHeader(headers, "Nats-Msg-Id"), data)
+			if reply == "" {
+				continue
+			}
+			for sid, subject := range subscriptions {
+				if natsSubjectMatches(subject, reply) {
+					write("MSG %s %s %d\r\n%s\r\n", reply, sid, len(ack), ack)
+				}
+			}
+		}
+	}
+}
+
+// store records a message and returns its JetStream publish acknowledgement.
+func (s *fakeNatsServer) store(subject string, msgID string, data []byte) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	duplicate := msgID != "" && s.msgIDs[msgID]
+	if !duplicate {
+		if msgID != "" {
+			s.msgIDs[msgID] = true
+		}
+		s.messages = append(s.messages, natsTestMessage{subject: subject, msgID: msgID, data: data})
+	}
+	ack, _ := json.Marshal(map[string]interface{}{"stream": "TXS", "seq": len(s.messages), "duplicate": duplicate})
+	return ack
+}
+
+func (s *fakeNatsServer) received() []natsTestMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]natsTestMessage(nil), s.messages...)
+}
+
+func natsHeader(headers string, key string) string {
+	for _, line := range strings.Split(headers, "\r\n") {
+		if name, value, ok := strings.Cut(line, ":"); ok && strings.EqualFold(name, key) {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+func natsSubjectMatches(pattern string, subject string) bool {
+	patternTokens, subjectTokens := strings.Split(pattern, "."), strings.Split(subject, ".")
+	for i, token := range patternTokens {
+		if token == ">" {
+			return len(subjectTokens) > i
+		}
+		if i >= len(subjectTokens) || token != "*" && token != subjectTokens[i] {
+			return false
+		}
+	}
+	return len(patternTokens) == len(subjectTokens)
+}
+
+func TestNatsPublisher(t *testing.T) {
+	s := runFakeNatsServer(t)
+	notifier, err := NewNatsPublisher(s.URL(), "txs", false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeNotifier(notifier)
+	if err := notifier.Notify(AlertData{ChainName: "Cosmos Hub", WalletAddress: "cosmos1abc", TxHash: "AB12"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var messages []natsTestMessage
+	waitFor(t, 5*time.Second, func() bool {
+		messages = s.received()
+		return len(messages) == 1
+	})
+	if want := "txs.Cosmos_Hub.cosmos1abc"; messages[0].subject != want {
+		t.Errorf("subject = %q, want %q", messages[0].subject, want)
+	}
+	var event TxEvent
+	if err := json.Unmarshal(messages[0].data, &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.TxHash != "AB12" || event.Key != "Cosmos Hub/cosmos1abc" {
+		t.Errorf("event = %+v", event)
+	}
+}
+
+func TestNatsPublisherJetStreamDeduplicates(t *testing.T) {
+	s := runFakeNatsServer(t)
+	notifier, err := NewNatsPublisher(s.URL(), "txs", true, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeNotifier(notifier)
+	alert := AlertData{ChainName: "kava", WalletAddress: "kava1abc", TxHash: "AB12"}
+	for _, alertData := range []AlertData{alert, alert, {ChainName: "kava", WalletAddress: "kava1abc", TxHash: "CD34"}} {
+		if err := notifier.Notify(alertData); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	waitFor(t, 5*time.Second, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.msgIDs) == 2 && len(s.messages) == 2
+	})
+	if messages := s.received(); messages[0].msgID != "AB12:kava1abc" || messages[1].msgID != "CD34:kava1abc" {
+		t.Errorf("message ids = %q, %q", messages[0].msgID, messages[1].msgID)
+	}
+}
@@ -0,0 +1,227 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"reflect"
+)
+
+// Notifier delivers a transformed transaction alert to a single destination.
+type Notifier interface {
+	Name() string
+	Notify(alertData AlertData) error
+}
+
+// notifierFunc adapts the Send*/Send*Summary functions of a chat platform to SummaryNotifier.
+type notifierFunc struct {
+	name        string
+	send        func(alertData AlertData) error
+	sendSummary func(summary Summary) error
+}
+
+func (n notifierFunc) Name() string {
+	return n.name
+}
+
+func (n notifierFunc) Notify(alertData AlertData) error {
+	return n.send(alertData)
+}
+
+func (n notifierFunc) NotifySummary(summary Summary) error {
+	return n.sendSummary(summary)
+}
+
+// closeNotifier releases the connections and goroutines of a notifier that has them.
+func closeNotifier(notifier Notifier) error {
+	if closer, ok := notifier.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// notifierSpec describes one notifier of the alerting config. settings holds everything
+// the notifier is built from, so that a reload only rebuilds notifiers whose settings changed.
+type notifierSpec struct {
+	key      string
+	enabled  bool
+	settings interface{}
+	build    func() (Notifier, error)
+}
+
+func notifierSpecs(alerting Alerting) []notifierSpec {
+	discordBot := alerting.Discord.Bot
+	slackBot := alerting.Slack.Bot
+	telegram := alerting.Telegram
+	matrix := alerting.Matrix
+	mattermost := alerting.Mattermost
+	nats := alerting.Nats
+	kafka := alerting.Kafka
+	redis := alerting.Redis
+	file := alerting.File
+	return []notifierSpec{
+		{"discord", alerting.Discord.Enable, alerting.Discord.WebhookURL, func() (Notifier, error) {
+			url := alerting.Discord.WebhookURL
+			return &notifierFunc{"Discord", func(alertData AlertData) error {
+				return SendDiscordWebhook(url, alertData)
+			}, func(summary Summary) error {
+				return SendDiscordSummary(url, summary)
+			}}, nil
+		}},
+		{"discord_bot", discordBot.Enable && discordBot.ChannelID != "", discordBot, func() (Notifier, error) {
+			return NewDiscordBotNotifier(discordBot.BotToken, discordBot.GuildID, discordBot.ChannelID, discordBot.ThreadPerWallet), nil
+		}},
+		{"slack", alerting.Slack.Enable, alerting.Slack.WebhookURL, func() (Notifier, error) {
+			url := alerting.Slack.WebhookURL
+			return &notifierFunc{"Slack", func(alertData AlertData) error {
+				return SendSlackWebhook(url, alertData)
+			}, func(summary Summary) error {
+				return SendSlackSummary(url, summary)
+			}}, nil
+		}},
+		{"slack_bot", slackBot.Enable, slackBot, func() (Notifier, error) {
+			return NewSlackBotNotifier(slackBot.BotToken, slackBot.Channels, slackBot.FollowUp), nil
+		}},
+		{"telegram", telegram.Enable, [2]string{telegram.BotToken, telegram.ChatID}, func() (Notifier, error) {
+			return &notifierFunc{"Telegram", func(alertData AlertData) error {
+				return SendTelegramMessage(telegram.BotToken, telegram.ChatID, alertData)
+			}, func(summary Summary) error {
+				return SendTelegramSummary(telegram.BotToken, telegram.ChatID, summary)
+			}}, nil
+		}},
+		{"matrix", matrix.Enable, matrix, func() (Notifier, error) {
+			return &notifierFunc{"Matrix", func(alertData AlertData) error {
+				return SendMatrixMessage(matrix.Homeserver, matrix.AccessToken, matrix.RoomID, alertData)
+			}, func(summary Summary) error {
+				return SendMatrixSummary(matrix.Homeserver, matrix.AccessToken, matrix.RoomID, summary)
+			}}, nil
+		}},
+		{"mattermost", mattermost.Enable, mattermost, func() (Notifier, error) {
+			return &notifierFunc{"Mattermost", func(alertData AlertData) error {
+				return SendMattermostWebhook(mattermost.WebhookURL, mattermost.Channel, alertData)
+			}, func(summary Summary) error {
+				return SendMattermostSummary(mattermost.WebhookURL, mattermost.Channel, summary)
+			}}, nil
+		}},
+		{"teams", alerting.Teams.Enable, alerting.Teams.WebhookURL, func() (Notifier, error) {
+			url := alerting.Teams.WebhookURL
+			return &notifierFunc{"Teams", func(alertData AlertData) error {
+				return SendTeamsWebhook(url, alertData)
+			}, func(summary Summary) error {
+				return SendTeamsSummary(url, summary)
+			}}, nil
+		}},
+		{"nats", nats.Enable, nats, func() (Notifier, error) {
+			return NewNatsPublisher(nats.URL, nats.Subject, nats.JetStream, nats.QueueDir)
+		}},
+		{"kafka", kafka.Enable, kafka, func() (Notifier, error) {
+			return NewKafkaPublisher(kafka.Brokers, kafka.Topic, kafka.QueueDir)
+		}},
+		{"redis", redis.Enable, redis, func() (Notifier, error) {
+			return NewRedisStreamPublisher(redis.Addr, redis.Password, redis.DB, redis.Stream, redis.MaxLen, redis.QueueDir)
+		}},
+		{"file", file.Enable, file, func() (Notifier, error) {
+			return NewFileSink(file.Path, file.MaxSizeMB, file.MaxBackups)
+		}},
+		{"stdout", alerting.Stdout.Enable, nil, func() (Notifier, error) {
+			return NewStdoutSink(), nil
+		}},
+		{"syslog", alerting.Syslog.Enable, alerting.Syslog.Tag, func() (Notifier, error) {
+			return NewSyslogSink(alerting.Syslog.Tag)
+		}},
+	}
+}
+
+// NewNotifiers builds the list of enabled notifiers from the alerting config.
+func NewNotifiers(alerting Alerting) []Notifier {
+	notifiers, _ := buildNotifiers(alerting, nil)
+	return notifiers
+}
+
+// builtNotifier is a notifier with the settings and rate limit it was built from.
+type builtNotifier struct {
+	settings  interface{}
+	rateLimit *RateLimit
+	notifier  Notifier
+}
+
+// buildNotifiers builds the enabled notifiers, reusing those of previous whose settings
+// and rate limit did not change so that they keep their connections, queues and state.
+func buildNotifiers(alerting Alerting, previous map[string]builtNotifier) ([]Notifier, map[string]builtNotifier) {
+	var notifiers []Notifier
+	built := make(map[string]builtNotifier)
+	for _, spec := range notifierSpecs(alerting) {
+		if !spec.enabled {
+			continue
+		}
+		var rateLimit *RateLimit
+		if limit, ok := alerting.RateLimits[spec.key]; ok {
+			rateLimit = &limit
+		}
+		if old, ok := previous[spec.key]; ok && reflect.DeepEqual(old.settings, spec.settings) && reflect.DeepEqual(old.rateLimit, rateLimit) {
+			notifiers = append(notifiers, old.notifier)
+			built[spec.key] = old
+			continue
+		}
+
+		notifier, err := spec.build()
+		if err != nil {
+			log.Printf("Error starting %s notifier: %v", spec.key, err)
+			continue
+		}
+		if rateLimit != nil {
+			notifier = newRateLimitedNotifier(notifier, *rateLimit)
+		}
+		notifiers = append(notifiers, notifier)
+		built[spec.key] = builtNotifier{settings: spec.settings, rateLimit: rateLimit, notifier: notifier}
+	}
+	return notifiers, built
+}
+
+// postJSON sends payload as a JSON body and treats any non-2xx status as an error.
+func postJSON(method, url string, payload interface{}, headers map[string]string) error {
+	jsonBytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewBuffer(jsonBytes))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("non-2xx status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// truncate cuts s to at most max bytes without splitting a UTF-8 sequence.
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	const suffix = "\n…(truncated)"
+	cut := max - len(suffix)
+	if cut < 0 {
+		cut = 0
+	}
+	for cut > 0 && (s[cut]&0xC0) == 0x80 {
+		cut--
+	}
+	return s[:cut] + suffix
+}
@@ -0,0 +1,264 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultCoinGeckoURL = "https://api.coingecko.com/api/v3"
+
+// Prices configures the fiat values shown next to amounts. The price file is asked
+// first, then CoinGecko.
+type Prices struct {
+	Currency  string `yaml:"currency"`  // e.g. "usd" (default)
+	CacheTTL  string `yaml:"cache_ttl"` // how long a price is used, default 5m
+	File      string `yaml:"file"`      // YAML of coingecko id or display denom -> price
+	CoinGecko struct {
+		Enable bool              `yaml:"enable"`
+		URL    string            `yaml:"url"` // any CoinGecko-compatible API
+		APIKey string            `yaml:"api_key"`
+		IDs    map[string]string `yaml:"ids"` // display denom -> coingecko id, for denoms without one in their metadata
+	} `yaml:"coingecko"`
+
+	book *priceBook
+}
+
+func (p *Prices) compile() error {
+	if p.File == "" && !p.CoinGecko.Enable {
+		return nil
+	}
+	book := &priceBook{currency: strings.ToLower(p.Currency), ttl: 5 * time.Minute, cache: make(map[PriceAsset]cachedPrice)}
+	if book.currency == "" {
+		book.currency = "usd"
+	}
+	if p.CacheTTL != "" {
+		ttl, err := time.ParseDuration(p.CacheTTL)
+		if err != nil || ttl <= 0 {
+			return fmt.Errorf("invalid cache_ttl %q", p.CacheTTL)
+		}
+		book.ttl = ttl
+	}
+	if p.File != "" {
+		file := &filePrices{path: p.File}
+		if err := file.load(); err != nil {
+			return err
+		}
+		book.sources = append(book.sources, file)
+	}
+	if p.CoinGecko.Enable {
+		book.sources = append(book.sources, &coinGeckoPrices{
+			client: &http.Client{Timeout: 15 * time.Second},
+			url:    strings.TrimSuffix(p.CoinGecko.URL, "/"),
+			apiKey: p.CoinGecko.APIKey,
+			ids:    p.CoinGecko.IDs,
+		})
+	}
+	p.book = book
+	return nil
+}
+
+// PriceAsset identifies an asset to price.
+type PriceAsset struct {
+	Denom       string // display denom, e.g. "atom"
+	CoinGeckoID string // from the denom's metadata, e.g. "cosmos"
+}
+
+// PriceSource returns the price of one display unit of an asset in a fiat currency;
+// ok is false when it does not know the asset.
+type PriceSource interface {
+	Price(asset PriceAsset, currency string) (price Decimal, ok bool, err error)
+}
+
+// FiatValue is the value of a coin in a fiat currency.
+type FiatValue struct {
+	Amount   Decimal `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+func (v FiatValue) String() string {
+	amount := v.Amount.Round(2).String()
+	if whole, fraction, found := strings.Cut(amount, "."); !found {
+		amount += ".00"
+	} else if len(fraction) == 1 {
+		amount = whole + "." + fraction + "0"
+	}
+	if v.Currency == "usd" {
+		return "$" + amount
+	}
+	return amount + " " + strings.ToUpper(v.Currency)
+}
+
+// priceBook asks its sources in order and caches what they answer, including that no
+// price is known, for the cache TTL. Sources are asked outside the lock, one at a time
+// per asset.
+type priceBook struct {
+	sources  []PriceSource
+	currency string
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	cache   map[PriceAsset]cachedPrice
+	flights singleflight.Group
+}
+
+type cachedPrice struct {
+	price   Decimal
+	ok      bool
+	expires time.Time
+}
+
+func (b *priceBook) Price(asset PriceAsset) (Decimal, bool) {
+	b.mu.Lock()
+	cached, found := b.cache[asset]
+	b.mu.Unlock()
+	if found && time.Now().Before(cached.expires) {
+		return cached.price, cached.ok
+	}
+
+	fetched, _, _ := b.flights.Do(asset.Denom+"\x00"+asset.CoinGeckoID, func() (interface{}, error) {
+		cached := cachedPrice{expires: time.Now().Add(b.ttl)}
+		for _, source := range b.sources {
+			price, ok, err := source.Price(asset, b.currency)
+			if err != nil {
+				log.Printf("Error fetching the price of %s: %v", asset.Denom, err)
+				continue
+			}
+			if ok {
+				cached.price, cached.ok = price, true
+				break
+			}
+		}
+		b.mu.Lock()
+		b.cache[asset] = cached
+		b.mu.Unlock()
+		return cached, nil
+	})
+	cached = fetched.(cachedPrice)
+	return cached.price, cached.ok
+}
+
+// value sets the fiat value of the coins whose price is known. A nil book leaves them as they are.
+func (b *priceBook) value(coins []Coin) []Coin {
+	if b == nil {
+		return coins
+	}
+	for i, coin := range coins {
+		if price, ok := b.Price(PriceAsset{Denom: coin.Denom, CoinGeckoID: coin.coinGeckoID}); ok {
+			coins[i].Value = &FiatValue{Amount: coin.Amount.Mul(price), Currency: b.currency}
+		}
+	}
+	return coins
+}
+
+// filePrices reads prices from a YAML file, again whenever it changes, for offline use
+// or for assets without a market.
+type filePrices struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	prices  map[string]Decimal
+}
+
+func (f *filePrices) load() error {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(f.modTime) {
+		return nil
+	}
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return err
+	}
+	var prices map[string]Decimal
+	if err := yaml.Unmarshal(data, &prices); err != nil {
+		return fmt.Errorf("%s: %v", f.path, err)
+	}
+	f.prices = make(map[string]Decimal, len(prices))
+	for key, price := range prices {
+		f.prices[strings.ToLower(key)] = price
+	}
+	f.modTime = info.ModTime()
+	return nil
+}
+
+func (f *filePrices) Price(asset PriceAsset, currency string) (Decimal, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.load(); err != nil {
+		return Decimal{}, false, err
+	}
+	for _, key := range []string{asset.CoinGeckoID, asset.Denom} {
+		if price, ok := f.prices[strings.ToLower(key)]; ok && key != "" {
+			return price, true, nil
+		}
+	}
+	return Decimal{}, false, nil
+}
+
+// coinGeckoPrices asks the /simple/price endpoint of a CoinGecko-compatible API.
+type coinGeckoPrices struct {
+	client *http.Client
+	url    string
+	apiKey string
+	ids    map[string]string
+}
+
+func (c *coinGeckoPrices) Price(asset PriceAsset, currency string) (Decimal, bool, error) {
+	id := c.ids[asset.Denom]
+	if id == "" {
+		id = asset.CoinGeckoID
+	}
+	if id == "" {
+		return Decimal{}, false, nil
+	}
+	baseURL := c.url
+	if baseURL == "" {
+		baseURL = defaultCoinGeckoURL
+	}
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/simple/price?ids=%s&vs_currencies=%s", baseURL, url.QueryEscape(id), url.QueryEscape(currency)), nil)
+	if err != nil {
+		return Decimal{}, false, err
+	}
+	if c.apiKey != "" {
+		header := "x-cg-demo-api-key"
+		if strings.Contains(baseURL, "pro-api.coingecko.com") {
+			header = "x-cg-pro-api-key"
+		}
+		req.Header.Set(header, c.apiKey)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Decimal{}, false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Decimal{}, false, fmt.Errorf("non-200 status code: %d", resp.StatusCode)
+	}
+
+	var prices map[string]map[string]json.Number
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&prices); err != nil {
+		return Decimal{}, false, err
+	}
+	number, ok := prices[id][currency]
+	if !ok {
+		return Decimal{}, false, nil
+	}
+	price, err := ParseDecimal(number.String())
+	return price, err == nil, err
+}
@@ -0,0 +1,337 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	publisherQueueSize  = 1000
+	publisherMaxBackoff = 1 * time.Minute
+)
+
+// TxEvent is the JSON document emitted to message-bus sinks.
+type TxEvent struct {
+	Key           string    `json:"key"`
+	ChainName     string    `json:"chain_name"`
+	WalletAddress string    `json:"wallet_address"`
+	TxHash        string    `json:"tx_hash"`
+	TxURL         string    `json:"tx_url"`
+	Failed        bool      `json:"failed"`
+	ObservedAt    time.Time `json:"observed_at"`
+	Alert         AlertData `json:"alert"`
+}
+
+func NewTxEvent(alertData AlertData) TxEvent {
+	return TxEvent{
+		Key:           eventKey(alertData.ChainName, alertData.WalletAddress),
+		ChainName:     alertData.ChainName,
+		WalletAddress: alertData.WalletAddress,
+		TxHash:        alertData.TxHash,
+		TxURL:         alertData.TxURL(),
+		Failed:        alertData.Error != "",
+		ObservedAt:    time.Now().UTC(),
+		Alert:         alertData,
+	}
+}
+
+// eventKey is used as the partition/message key so events of one wallet stay ordered.
+func eventKey(chainName, walletAddress string) string {
+	return chainName + "/" + walletAddress
+}
+
+// publisher queues events and retries each one until the broker acknowledges it.
+// Notify never blocks the other notifiers. With a queue directory every event is kept
+// in a file until it is published, so events survive restarts and reloads and are
+// delivered at least once. Without one the queue is in memory: when the broker is down
+// long enough for publisherQueueSize events to pile up, new events are dropped and
+// reported as failed, and events still queued on Close get one last attempt.
+type publisher struct {
+	name    string
+	store   *eventStore // nil keeps the queue in memory
+	publish func(event TxEvent, payload []byte) error
+	close   func() error
+
+	mu      sync.Mutex
+	queue   []queuedEvent
+	dropped int
+	wake    chan struct{}
+
+	done      chan struct{} // closed by Close
+	closeOnce sync.Once
+}
+
+// queuedEvent is an event in memory, or in file when the queue is stored.
+type queuedEvent struct {
+	event *TxEvent
+	file  string
+}
+
+// newPublisher starts a publisher. A non-empty queueDir stores its queue in a directory
+// named after the publisher in it, and first sends the events left there.
+func newPublisher(name string, queueDir string, publish func(event TxEvent, payload []byte) error, close func() error) (*publisher, error) {
+	p := &publisher{
+		name:    name,
+		publish: publish,
+		close:   close,
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	if queueDir != "" {
+		store, files, err := openEventStore(filepath.Join(queueDir, strings.ToLower(name)))
+		if err != nil {
+			return nil, fmt.Errorf("%s queue: %v", name, err)
+		}
+		p.store = store
+		for _, file := range files {
+			p.queue = append(p.queue, queuedEvent{file: file})
+		}
+		if len(files) > 0 {
+			log.Printf("Resending %d queued events of %s", len(files), name)
+		}
+	}
+	go p.run()
+	return p, nil
+}
+
+func (p *publisher) Name() string {
+	return p.name
+}
+
+// Notify queues the event. It fails when the publisher is closed, when the event cannot
+// be stored, or when the in-memory queue is full, in which case the event is dropped.
+func (p *publisher) Notify(alertData AlertData) error {
+	select {
+	case <-p.done:
+		return fmt.Errorf("%s publisher is closed", p.name)
+	default:
+	}
+	event := NewTxEvent(alertData)
+	item := queuedEvent{event: &event}
+	if p.store != nil {
+		file, err := p.store.put(event)
+		if err != nil {
+			return fmt.Errorf("event %s dropped (%d dropped so far): %v", event.TxHash, p.drop(), err)
+		}
+		item = queuedEvent{file: file}
+	}
+
+	p.mu.Lock()
+	if p.store == nil && len(p.queue) >= publisherQueueSize {
+		p.mu.Unlock()
+		return fmt.Errorf("%s queue is full (%d events), event %s dropped (%d dropped so far)", p.name, publisherQueueSize, event.TxHash, p.drop())
+	}
+	p.queue = append(p.queue, item)
+	p.mu.Unlock()
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// drop counts a dropped event and returns how many were dropped so far.
+func (p *publisher) drop() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dropped++
+	return p.dropped
+}
+
+// Close stops accepting events and retrying them before the connection is closed.
+// Stored events stay for the next publisher of the queue directory; events in memory
+// get one more attempt each, until the first that fails.
+func (p *publisher) Close() error {
+	p.closeOnce.Do(func() { close(p.done) })
+	return nil
+}
+
+func (p *publisher) run() {
+	for {
+		// Once closed, queued events are left to shutdown.
+		select {
+		case <-p.done:
+			p.shutdown()
+			return
+		default:
+		}
+		p.mu.Lock()
+		if len(p.queue) == 0 {
+			p.mu.Unlock()
+			select {
+			case <-p.wake:
+			case <-p.done:
+			}
+			continue
+		}
+		item := p.queue[0]
+		p.mu.Unlock()
+
+		if p.deliver(item, true) {
+			p.mu.Lock()
+			p.queue = p.queue[1:]
+			p.mu.Unlock()
+		}
+	}
+}
+
+// shutdown gives the events queued in memory one attempt each, stopping at the first
+// failure as the broker is likely down, and closes the connection.
+func (p *publisher) shutdown() {
+	p.mu.Lock()
+	queue := p.queue
+	p.queue = nil
+	p.mu.Unlock()
+
+	if p.store != nil {
+		if len(queue) > 0 {
+			log.Printf("Kept %d queued events of %s in %s", len(queue), p.name, p.store.dir)
+		}
+	} else {
+		for i, item := range queue {
+			if !p.deliver(item, false) {
+				p.mu.Lock()
+				p.dropped += len(queue) - i
+				dropped := p.dropped
+				p.mu.Unlock()
+				log.Printf("Dropped %d queued events of %s (%d dropped so far)", len(queue)-i, p.name, dropped)
+				break
+			}
+		}
+	}
+	if err := p.close(); err != nil {
+		log.Printf("Error closing %s: %v", p.name, err)
+	}
+}
+
+// deliver publishes an event and removes its file, retrying with backoff until it
+// succeeds or the publisher is closed when retry is set. It reports whether the event
+// is done with, which includes stored events that cannot be read.
+func (p *publisher) deliver(item queuedEvent, retry bool) bool {
+	event := item.event
+	if event == nil {
+		stored, err := p.store.get(item.file)
+		if os.IsNotExist(err) {
+			return true // published by another publisher of the same directory
+		}
+		if err != nil {
+			log.Printf("Dropped unreadable event %s of %s: %v", item.file, p.name, err)
+			p.store.remove(item.file)
+			return true
+		}
+		event = &stored
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Error encoding event for %s: %v", p.name, err)
+		return true
+	}
+
+	backoff := 1 * time.Second
+	for {
+		err := p.publish(*event, payload)
+		if err == nil {
+			log.Printf("Event %s published to %s", event.TxHash, p.name)
+			if item.file != "" {
+				p.store.remove(item.file)
+			}
+			return true
+		}
+		if !retry {
+			log.Printf("Error publishing event %s to %s: %v", event.TxHash, p.name, err)
+			return false
+		}
+		log.Printf("Error publishing event %s to %s: %v. Retrying in %s", event.TxHash, p.name, err, backoff)
+		select {
+		case <-time.After(backoff):
+		case <-p.done:
+			return false
+		}
+		if backoff *= 2; backoff > publisherMaxBackoff {
+			backoff = publisherMaxBackoff
+		}
+	}
+}
+
+// eventStore keeps queued events as one file each, named so that they sort in the
+// order they were queued.
+type eventStore struct {
+	dir string
+
+	mu  sync.Mutex
+	seq int
+}
+
+// openEventStore creates dir if needed and returns the events left in it, oldest first.
+// Files of events that were being written when the process stopped are removed.
+func openEventStore(dir string) (*eventStore, []string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, nil, err
+	}
+	partial, _ := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	for _, file := range partial {
+		os.Remove(file)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(files)
+	return &eventStore{dir: dir}, files, nil
+}
+
+// put writes the event to a new file, synced to disk before it is renamed into place,
+// and returns the file.
+func (s *eventStore) put(event TxEvent) (string, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	s.seq++
+	name := fmt.Sprintf("%019d-%06d.json", time.Now().UnixNano(), s.seq%1000000)
+	s.mu.Unlock()
+
+	file := filepath.Join(s.dir, name)
+	tmp, err := os.CreateTemp(s.dir, name+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), file)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return file, nil
+}
+
+func (s *eventStore) get(file string) (TxEvent, error) {
+	var event TxEvent
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return event, err
+	}
+	return event, json.Unmarshal(data, &event)
+}
+
+func (s *eventStore) remove(file string) {
+	if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+		log.Printf("Error removing queued event %s: %v", file, err)
+	}
+}
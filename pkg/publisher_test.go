@@ -0,0 +1,174 @@
+package pkg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it holds or the timeout passes.
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func mustPublisher(t *testing.T, queueDir string, publish func(event TxEvent, payload []byte) error, close func() error) *publisher {
+	t.Helper()
+	p, err := newPublisher("test", queueDir, publish, close)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestPublisherRetriesUntilPublished(t *testing.T) {
+	var attempts, published atomic.Int32
+	p := mustPublisher(t, "", func(event TxEvent, payload []byte) error {
+		if attempts.Add(1) == 1 {
+			return errors.New("broker down")
+		}
+		published.Add(1)
+		return nil
+	}, func() error { return nil })
+	defer p.Close()
+
+	if err := p.Notify(AlertData{TxHash: "A"}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, 5*time.Second, func() bool { return published.Load() == 1 })
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
+
+func TestPublisherDropsWhenQueueIsFull(t *testing.T) {
+	release := make(chan struct{})
+	p := mustPublisher(t, "", func(event TxEvent, payload []byte) error {
+		<-release
+		return nil
+	}, func() error { return nil })
+	defer p.Close()
+	defer close(release)
+
+	// The first event blocks in publish and stays queued until it is published.
+	for i := 0; i < publisherQueueSize; i++ {
+		if err := p.Notify(AlertData{}); err != nil {
+			t.Fatalf("event %d: %v", i, err)
+		}
+	}
+
+	done := make(chan error)
+	go func() { done <- p.Notify(AlertData{}) }()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Notify on a full queue succeeded")
+		}
+		if p.dropped != 1 {
+			t.Errorf("dropped = %d, want 1", p.dropped)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Notify blocked on a full queue")
+	}
+}
+
+func TestPublisherCloseStopsRetrying(t *testing.T) {
+	closed := make(chan struct{})
+	var attempts atomic.Int32
+	p := mustPublisher(t, "", func(event TxEvent, payload []byte) error {
+		attempts.Add(1)
+		return errors.New("broker down")
+	}, func() error {
+		close(closed)
+		return nil
+	})
+
+	for i := 0; i < 3; i++ {
+		if err := p.Notify(AlertData{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor(t, time.Second, func() bool { return attempts.Load() == 1 })
+	p.Close()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("publisher did not close its connection")
+	}
+	// The retried event gets one last attempt, which fails, and the others are dropped.
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+	if err := p.Notify(AlertData{}); err == nil {
+		t.Error("Notify after Close succeeded")
+	}
+}
+
+func TestPublisherKeepsStoredEventsAcrossRestarts(t *testing.T) {
+	dir := t.TempDir()
+	down := mustPublisher(t, dir, func(event TxEvent, payload []byte) error {
+		return errors.New("broker down")
+	}, func() error { return nil })
+	for _, hash := range []string{"A", "B", "C"} {
+		if err := down.Notify(AlertData{TxHash: hash}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	down.Close()
+
+	published := make(chan string, 3)
+	up := mustPublisher(t, dir, func(event TxEvent, payload []byte) error {
+		published <- event.TxHash
+		return nil
+	}, func() error { return nil })
+	defer up.Close()
+	for _, want := range []string{"A", "B", "C"} {
+		select {
+		case got := <-published:
+			if got != want {
+				t.Errorf("published %s, want %s", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("event %s was not resent", want)
+		}
+	}
+	waitFor(t, time.Second, func() bool {
+		files, _ := filepath.Glob(filepath.Join(dir, "test", "*"))
+		return len(files) == 0
+	})
+}
+
+func TestPublisherSkipsUnreadableStoredEvents(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "test"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string]string{"1-000001.json": "{", "2-000002.json": `{"tx_hash":"B"}`, "3.json.123.tmp": "{"} {
+		if err := os.WriteFile(filepath.Join(dir, "test", name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	published := make(chan string, 2)
+	p := mustPublisher(t, dir, func(event TxEvent, payload []byte) error {
+		published <- event.TxHash
+		return nil
+	}, func() error { return nil })
+	defer p.Close()
+	select {
+	case got := <-published:
+		if got != "B" {
+			t.Errorf("published %s, want B", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("stored event was not resent")
+	}
+}
@@ -0,0 +1,254 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	OverflowQueue    = "queue"
+	OverflowCollapse = "collapse"
+	OverflowDrop     = "drop"
+
+	defaultRateLimitQueueSize = 1000
+)
+
+// errQueued is returned for an alert or summary that a rate limit holds back to send
+// later, so that callers do not report it as sent.
+var errQueued = errors.New("queued by rate limit")
+
+// RateLimit is a token bucket for one destination: Burst messages at once, refilled
+// at PerMinute. Alerts beyond it are queued, collapsed into a "N more transactions"
+// summary, or dropped and counted. Digests take tokens too, and are queued or dropped.
+type RateLimit struct {
+	PerMinute float64 `yaml:"per_minute"`
+	Burst     int     `yaml:"burst"`
+	Overflow  string  `yaml:"overflow"`   // queue, collapse or drop
+	QueueSize int     `yaml:"queue_size"` // for queue and collapse
+}
+
+func (r RateLimit) validate() error {
+	if r.PerMinute <= 0 {
+		return fmt.Errorf("per_minute must be positive")
+	}
+	switch r.Overflow {
+	case "", OverflowQueue, OverflowCollapse, OverflowDrop:
+		return nil
+	default:
+		return fmt.Errorf("unknown overflow %q", r.Overflow)
+	}
+}
+
+type tokenBucket struct {
+	rate     float64 // tokens per second
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(perMinute float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: perMinute / 60, capacity: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+func (b *tokenBucket) refill() {
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+}
+
+func (b *tokenBucket) take() bool {
+	b.refill()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// delay is how long until the next token is available.
+func (b *tokenBucket) delay() time.Duration {
+	b.refill()
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// rateLimitedNotifier applies a RateLimit in front of a notifier.
+type rateLimitedNotifier struct {
+	Notifier
+	overflow  string
+	queueSize int
+
+	mu      sync.Mutex
+	bucket  *tokenBucket
+	pending []rateLimitedItem
+	dropped int
+	wake    chan struct{}
+	closed  bool
+}
+
+// rateLimitedItem is a pending alert, or a summary when summary is set.
+type rateLimitedItem struct {
+	alert   AlertData
+	summary *Summary
+}
+
+func (i rateLimitedItem) String() string {
+	if i.summary != nil {
+		return fmt.Sprintf("summary %q", i.summary.Title)
+	}
+	return i.alert.TxHash
+}
+
+// rateLimitedSummaryNotifier keeps the SummaryNotifier capability of the wrapped notifier.
+type rateLimitedSummaryNotifier struct {
+	*rateLimitedNotifier
+	summary SummaryNotifier
+}
+
+func (n rateLimitedSummaryNotifier) NotifySummary(summary Summary) error {
+	return n.submit(rateLimitedItem{summary: &summary}, func() error {
+		return n.summary.NotifySummary(summary)
+	})
+}
+
+func newRateLimitedNotifier(notifier Notifier, limit RateLimit) Notifier {
+	overflow := limit.Overflow
+	if overflow == "" {
+		overflow = OverflowQueue
+	}
+	summaryNotifier, canSummarize := notifier.(SummaryNotifier)
+	if overflow == OverflowCollapse && !canSummarize {
+		log.Printf("%s cannot send summaries, queueing instead of collapsing", notifier.Name())
+		overflow = OverflowQueue
+	}
+	queueSize := limit.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultRateLimitQueueSize
+	}
+
+	n := &rateLimitedNotifier{
+		Notifier:  notifier,
+		overflow:  overflow,
+		queueSize: queueSize,
+		bucket:    newTokenBucket(limit.PerMinute, limit.Burst),
+		wake:      make(chan struct{}, 1),
+	}
+	if overflow != OverflowDrop {
+		go n.run()
+	}
+	if canSummarize {
+		return rateLimitedSummaryNotifier{rateLimitedNotifier: n, summary: summaryNotifier}
+	}
+	return n
+}
+
+func (n *rateLimitedNotifier) Notify(alertData AlertData) error {
+	return n.submit(rateLimitedItem{alert: alertData}, func() error {
+		return n.Notifier.Notify(alertData)
+	})
+}
+
+// submit sends an item right away when a token is free and nothing is waiting. Otherwise
+// it queues the item and returns errQueued, or drops it and returns an error.
+func (n *rateLimitedNotifier) submit(item rateLimitedItem, send func() error) error {
+	n.mu.Lock()
+	if n.closed {
+		n.mu.Unlock()
+		return fmt.Errorf("%s is closed", n.Name())
+	}
+	if len(n.pending) == 0 && n.bucket.take() {
+		n.mu.Unlock()
+		return send()
+	}
+	defer n.mu.Unlock()
+
+	if n.overflow == OverflowDrop || len(n.pending) >= n.queueSize {
+		n.dropped++
+		return fmt.Errorf("rate limit exceeded, dropped %s (%d dropped so far)", item, n.dropped)
+	}
+	n.pending = append(n.pending, item)
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+	return errQueued
+}
+
+// Close stops accepting alerts. Pending ones are still sent before the wrapped
+// notifier is closed.
+func (n *rateLimitedNotifier) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed {
+		return nil
+	}
+	n.closed = true
+	if n.overflow == OverflowDrop {
+		return closeNotifier(n.Notifier)
+	}
+	close(n.wake)
+	return nil
+}
+
+// run sends pending items as tokens become available: one by one when queueing, or the
+// alerts pending up to the next summary as one summary when collapsing.
+func (n *rateLimitedNotifier) run() {
+	for range n.wake {
+		for {
+			n.mu.Lock()
+			if len(n.pending) == 0 {
+				n.mu.Unlock()
+				break
+			}
+			if delay := n.bucket.delay(); delay > 0 {
+				n.mu.Unlock()
+				time.Sleep(delay)
+				continue
+			}
+			n.bucket.take()
+			size := 1
+			if n.overflow == OverflowCollapse && n.pending[0].summary == nil {
+				for size < len(n.pending) && n.pending[size].summary == nil {
+					size++
+				}
+			}
+			batch := n.pending[:size]
+			n.pending = n.pending[size:]
+			n.mu.Unlock()
+
+			var err error
+			switch {
+			case batch[0].summary != nil:
+				err = n.Notifier.(SummaryNotifier).NotifySummary(*batch[0].summary)
+			case len(batch) == 1:
+				err = n.Notifier.Notify(batch[0].alert)
+			default:
+				alerts := make([]AlertData, len(batch))
+				for i, item := range batch {
+					alerts[i] = item.alert
+				}
+				summary := NewSummary(fmt.Sprintf("%d more transactions", len(alerts)), time.Time{}, time.Time{}, alerts)
+				err = n.Notifier.(SummaryNotifier).NotifySummary(summary)
+			}
+			if err != nil {
+				log.Printf("Error sending message to %s: %v", n.Name(), err)
+			} else {
+				log.Printf("Message sent to %s successfully", n.Name())
+			}
+		}
+	}
+	if err := closeNotifier(n.Notifier); err != nil {
+		log.Printf("Error closing %s: %v", n.Name(), err)
+	}
+}
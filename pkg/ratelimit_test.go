@@ -0,0 +1,111 @@
+package pkg
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingNotifier struct {
+	mu        sync.Mutex
+	alerts    []string
+	summaries []string
+}
+
+func (n *recordingNotifier) Name() string { return "recording" }
+
+func (n *recordingNotifier) Notify(alertData AlertData) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.alerts = append(n.alerts, alertData.TxHash)
+	return nil
+}
+
+func (n *recordingNotifier) NotifySummary(summary Summary) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.summaries = append(n.summaries, summary.Title)
+	return nil
+}
+
+func (n *recordingNotifier) sent() (int, int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.alerts), len(n.summaries)
+}
+
+func TestRateLimitReportsQueuedAlertsAndSummaries(t *testing.T) {
+	recorder := &recordingNotifier{}
+	notifier := newRateLimitedNotifier(recorder, RateLimit{PerMinute: 600, Burst: 1}).(SummaryNotifier)
+	defer closeNotifier(notifier)
+
+	if err := notifier.Notify(AlertData{TxHash: "A"}); err != nil {
+		t.Fatalf("first alert: %v", err)
+	}
+	if err := notifier.Notify(AlertData{TxHash: "B"}); !errors.Is(err, errQueued) {
+		t.Fatalf("second alert: got %v, want errQueued", err)
+	}
+	if err := notifier.NotifySummary(Summary{Title: "daily digest"}); !errors.Is(err, errQueued) {
+		t.Fatalf("summary: got %v, want errQueued", err)
+	}
+	waitFor(t, 5*time.Second, func() bool {
+		alerts, summaries := recorder.sent()
+		return alerts == 2 && summaries == 1
+	})
+}
+
+func TestRateLimitDropsSummaries(t *testing.T) {
+	recorder := &recordingNotifier{}
+	notifier := newRateLimitedNotifier(recorder, RateLimit{PerMinute: 1, Burst: 1, Overflow: OverflowDrop}).(SummaryNotifier)
+	defer closeNotifier(notifier)
+
+	if err := notifier.NotifySummary(Summary{Title: "first"}); err != nil {
+		t.Fatalf("first summary: %v", err)
+	}
+	if err := notifier.NotifySummary(Summary{Title: "second"}); err == nil || errors.Is(err, errQueued) {
+		t.Fatalf("second summary: got %v, want a drop error", err)
+	}
+	if alerts, summaries := recorder.sent(); alerts != 0 || summaries != 1 {
+		t.Errorf("sent %d alerts and %d summaries, want 0 and 1", alerts, summaries)
+	}
+}
+
+func TestDispatchRecordsQueuedAlerts(t *testing.T) {
+	recorder := &recordingNotifier{}
+	limited := newRateLimitedNotifier(recorder, RateLimit{PerMinute: 1, Burst: 1})
+	defer closeNotifier(limited)
+	router := NewRouter(nil, []Notifier{limited})
+
+	if status := router.Dispatch(AlertData{TxHash: "A"}); status != AlertDelivered {
+		t.Errorf("first alert: status %s, want %s", status, AlertDelivered)
+	}
+	if status := router.Dispatch(AlertData{TxHash: "B"}); status != AlertQueued {
+		t.Errorf("second alert: status %s, want %s", status, AlertQueued)
+	}
+}
+
+type followUpRecorder struct {
+	recordingNotifier
+	followUps int
+}
+
+func (n *followUpRecorder) NotifyFollowUps(alertData AlertData) bool {
+	n.followUps++
+	return true
+}
+
+func TestFollowUpReachesRateLimitedNotifiers(t *testing.T) {
+	recorder := &followUpRecorder{}
+	limited := newRateLimitedNotifier(recorder, RateLimit{PerMinute: 1, Burst: 1})
+	defer closeNotifier(limited)
+	router := NewRouter(nil, []Notifier{limited, &recordingNotifier{}})
+
+	router.FollowUp(AlertData{TxHash: "A"})
+	if recorder.followUps != 1 {
+		t.Errorf("%d follow-ups, want 1", recorder.followUps)
+	}
+	if alerts, _ := recorder.sent(); alerts != 0 {
+		t.Errorf("%d alerts sent, want none", alerts)
+	}
+}
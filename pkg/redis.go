@@ -0,0 +1,40 @@
+package pkg
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NewRedisStreamPublisher appends events to a Redis Stream with XADD.
+func NewRedisStreamPublisher(addr string, password string, db int, stream string, maxLen int64, queueDir string) (Notifier, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+
+	p, err := newPublisher("Redis", queueDir, func(event TxEvent, payload []byte) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		return client.XAdd(ctx, &redis.XAddArgs{
+			Stream: stream,
+			MaxLen: maxLen,
+			Approx: maxLen > 0,
+			Values: map[string]interface{}{
+				"key":     event.Key,
+				"chain":   event.ChainName,
+				"wallet":  event.WalletAddress,
+				"tx_hash": event.TxHash,
+				"event":   payload,
+			},
+		}).Err()
+	}, client.Close)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return p, nil
+}
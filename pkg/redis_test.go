@@ -0,0 +1,165 @@
+package pkg
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis answers XADD like a Redis server and rejects every other command, which
+// the client tolerates for its connection setup (HELLO, CLIENT SETINFO).
+type fakeRedis struct {
+	listener net.Listener
+
+	mu      sync.Mutex
+	entries map[string][]map[string]string // stream -> entries
+}
+
+func runFakeRedis(t *testing.T, addr string) *fakeRedis {
+	t.Helper()
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	r := &fakeRedis{listener: listener, entries: make(map[string][]map[string]string)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go r.serve(conn)
+		}
+	}()
+	return r
+}
+
+func (r *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readRESPArray(reader)
+		if err != nil {
+			return
+		}
+		if len(args) == 0 || !strings.EqualFold(args[0], "XADD") {
+			fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", strings.Join(args, " "))
+			continue
+		}
+		// XADD <stream> [MAXLEN [~|=] <count>] <id> <field> <value> ...
+		stream, rest := args[1], args[2:]
+		if strings.EqualFold(rest[0], "MAXLEN") {
+			rest = rest[1:]
+			if rest[0] == "~" || rest[0] == "=" {
+				rest = rest[1:]
+			}
+			rest = rest[1:]
+		}
+		entry := make(map[string]string)
+		for i := 1; i+1 < len(rest); i += 2 {
+			entry[rest[i]] = rest[i+1]
+		}
+		r.mu.Lock()
+		r.entries[stream] = append(r.entries[stream], entry)
+		id := fmt.Sprintf("%d-0", len(r.entries[stream]))
+		r.mu.Unlock()
+		fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(id), id)
+	}
+}
+
+func (r *fakeRedis) stream(name string) []map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]map[string]string(nil), r.entries[name]...)
+}
+
+// readRESPArray reads a command sent as an array of bulk strings.
+func readRESPArray(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("unexpected %q", line)
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, count)
+	for i := range args {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "$")))
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
+
+func TestRedisStreamPublisher(t *testing.T) {
+	r := runFakeRedis(t, "127.0.0.1:0")
+	notifier, err := NewRedisStreamPublisher(r.listener.Addr().String(), "", 0, "txs", 100, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeNotifier(notifier)
+
+	if err := notifier.Notify(AlertData{ChainName: "kava", WalletAddress: "kava1abc", TxHash: "AB12"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []map[string]string
+	waitFor(t, 5*time.Second, func() bool {
+		entries = r.stream("txs")
+		return len(entries) == 1
+	})
+	if entries[0]["key"] != "kava/kava1abc" || entries[0]["tx_hash"] != "AB12" {
+		t.Errorf("entry = %v", entries[0])
+	}
+	var event TxEvent
+	if err := json.Unmarshal([]byte(entries[0]["event"]), &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Alert.TxHash != "AB12" {
+		t.Errorf("event = %+v", event)
+	}
+}
+
+func TestRedisStreamPublisherRetriesWhileDown(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	notifier, err := NewRedisStreamPublisher(addr, "", 0, "txs", 0, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeNotifier(notifier)
+	if err := notifier.Notify(AlertData{ChainName: "kava", WalletAddress: "kava1abc", TxHash: "AB12"}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	r := runFakeRedis(t, addr)
+
+	waitFor(t, 10*time.Second, func() bool { return len(r.stream("txs")) == 1 })
+}
@@ -0,0 +1,105 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// registryChain is the part of a chain-registry chain.json the monitor uses.
+type registryChain struct {
+	ChainName    string `json:"chain_name"`
+	Bech32Prefix string `json:"bech32_prefix"`
+	APIs         struct {
+		RPC  []registryEndpoint `json:"rpc"`
+		Rest []registryEndpoint `json:"rest"`
+		GRPC []registryEndpoint `json:"grpc"`
+	} `json:"apis"`
+	Explorers []struct {
+		Kind   string `json:"kind"`
+		TxPage string `json:"tx_page"`
+	} `json:"explorers"`
+}
+
+type registryEndpoint struct {
+	Address  string `json:"address"`
+	Provider string `json:"provider"`
+}
+
+// registryAssetList is the part of a chain-registry assetlist.json the monitor uses.
+type registryAssetList struct {
+	Assets []denomMetadataJSON `json:"assets"`
+}
+
+// resolveRegistry fills the settings of a chain that are not in the config from its
+// chain.json and assetlist.json in a checkout of github.com/cosmos/chain-registry.
+// Settings in the config take precedence; see denomMetadata for denoms.
+func (c *ChainConfig) resolveRegistry(registryPath string) error {
+	if registryPath == "" {
+		return fmt.Errorf("chain_registry is not set")
+	}
+	dir := filepath.Join(registryPath, c.Registry)
+	if _, err := os.Stat(filepath.Join(dir, "chain.json")); os.IsNotExist(err) {
+		dir = filepath.Join(registryPath, "testnets", c.Registry)
+	}
+
+	var chain registryChain
+	if err := readJSONFile(filepath.Join(dir, "chain.json"), &chain); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("chain %q not found in %s", c.Registry, registryPath)
+		}
+		return err
+	}
+	if c.RPC == "" && len(chain.APIs.RPC) > 0 {
+		c.RPC = strings.TrimSuffix(chain.APIs.RPC[0].Address, "/")
+	}
+	if c.API == "" && len(chain.APIs.Rest) > 0 {
+		c.API = strings.TrimSuffix(chain.APIs.Rest[0].Address, "/")
+	}
+	if c.GRPC == "" && len(chain.APIs.GRPC) > 0 {
+		c.GRPC = chain.APIs.GRPC[0].Address
+	}
+	if c.Explorer == "" {
+		for _, explorer := range chain.Explorers {
+			if explorer.TxPage != "" {
+				c.Explorer = explorer.TxPage
+				break
+			}
+		}
+	}
+	if c.Prefix == "" {
+		c.Prefix = chain.Bech32Prefix
+	}
+
+	var assets registryAssetList
+	if err := readJSONFile(filepath.Join(dir, "assetlist.json"), &assets); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	c.registryDenoms = make(map[string]DenomMetadata)
+	for _, asset := range assets.Assets {
+		c.registryDenoms[asset.Base] = asset.metadata()
+	}
+	return nil
+}
+
+func readJSONFile(path string, value interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return nil
+}
+
+// TxURL links a transaction on the chain's explorer. The explorer URL is either a
+// template with ${txHash}, as in the chain registry, or a prefix the hash is appended to.
+func (a AlertData) TxURL() string {
+	if strings.Contains(a.ExplorerURL, "${txHash}") {
+		return strings.ReplaceAll(a.ExplorerURL, "${txHash}", a.TxHash)
+	}
+	return a.ExplorerURL + a.TxHash
+}
@@ -0,0 +1,184 @@
+package pkg
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const configPollInterval = 5 * time.Second
+
+// runtimeState is what alert processing and bot commands read for every alert or
+// command. A reload swaps it as a whole.
+type runtimeState struct {
+	cfg       *Config
+	router    *Router
+	notifiers map[string]builtNotifier
+}
+
+var current atomic.Pointer[runtimeState]
+
+// currentConfig returns the config in effect, which may have been reloaded since startup.
+func currentConfig() *Config {
+	return current.Load().cfg
+}
+
+// subscriptionKey identifies a running subscription; a wallet whose RPC or queries
+// change is resubscribed.
+type subscriptionKey struct {
+	chainName string
+	address   string
+	rpc       string
+	queries   string
+}
+
+type subscriptionSet struct {
+	mu      sync.Mutex
+	running map[subscriptionKey]func()
+}
+
+var subscriptions = &subscriptionSet{running: make(map[subscriptionKey]func())}
+
+// Apply starts the subscriptions of cfg that are not running yet and stops the others.
+func (s *subscriptionSet) Apply(cfg *Config) (started int, stopped int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wanted := make(map[subscriptionKey]bool)
+	for chainName, chain := range cfg.Chains {
+		for _, walletInfo := range chain.WalletInfo {
+			key := subscriptionKey{
+				chainName: chainName,
+				address:   walletInfo.WalletAddress,
+				rpc:       chain.RPC,
+				queries:   strings.Join(subscriptionQueries(cfg, walletInfo.WalletAddress), "\n"),
+			}
+			wanted[key] = true
+			if _, ok := s.running[key]; !ok {
+				s.running[key] = SubscribeToNewBlocks(cfg, chain, chainName, walletInfo.WalletAddress)
+				started++
+			}
+		}
+	}
+	for key, stop := range s.running {
+		if !wanted[key] {
+			stop()
+			delete(s.running, key)
+			stopped++
+		}
+	}
+	return started, stopped
+}
+
+// start puts cfg in effect: the first time on startup, then on every reload.
+func start(cfg *Config, previous *runtimeState) {
+	var oldNotifiers map[string]builtNotifier
+	if previous != nil {
+		oldNotifiers = previous.notifiers
+	}
+	notifiers, built := buildNotifiers(cfg.Alerting, oldNotifiers)
+	router := NewRouter(cfg.Alerting.Routes, notifiers)
+	if previous != nil {
+		router.Replace(previous.router)
+	}
+
+	mutes.SetConfigured(cfg.Alerting.Mutes)
+	addressBook.Set(cfg.labels)
+	if cfg.CacheDir != "" {
+		if err := denomTraces.SetFile(filepath.Join(cfg.CacheDir, "denom_traces.json")); err != nil {
+			log.Printf("Error loading denom traces: %v", err)
+		}
+	}
+	current.Store(&runtimeState{cfg: cfg, router: router, notifiers: built})
+
+	for key, old := range oldNotifiers {
+		if built[key].notifier != old.notifier {
+			log.Printf("Closing replaced %s notifier", key)
+			if err := closeNotifier(old.notifier); err != nil {
+				log.Printf("Error closing %s notifier: %v", key, err)
+			}
+		}
+	}
+	started, stopped := subscriptions.Apply(cfg)
+	if previous != nil {
+		log.Printf("Config reloaded: %d subscriptions started, %d stopped, %d notifiers enabled", started, stopped, len(notifiers))
+	}
+}
+
+var reloadMu sync.Mutex
+
+// ReloadConfig loads the config file again and puts it in effect. An invalid file is
+// reported and the running config is kept.
+func ReloadConfig() error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	previous := current.Load()
+	cfg, err := LoadConfig(previous.cfg.path)
+	if err != nil {
+		log.Printf("Config reload failed, keeping the running config: %v", err)
+		return err
+	}
+	if err := EnableSinks(cfg, previous.cfg.sinks); err != nil {
+		log.Printf("Config reload failed, keeping the running config: %v", err)
+		return err
+	}
+	for _, setting := range restartRequired(previous.cfg, cfg) {
+		log.Printf("Config reload: changes to %s take effect after a restart", setting)
+	}
+	start(cfg, previous)
+	return nil
+}
+
+// restartRequired lists the changed settings of servers and bots that are started once.
+func restartRequired(old *Config, cfg *Config) []string {
+	var settings []string
+	if !reflect.DeepEqual(old.Admin, cfg.Admin) {
+		settings = append(settings, "admin")
+	}
+	if old.Alerting.Telegram.Commands.Enable != cfg.Alerting.Telegram.Commands.Enable ||
+		cfg.Alerting.Telegram.Commands.Enable && (old.Alerting.Telegram.BotToken != cfg.Alerting.Telegram.BotToken ||
+			old.Alerting.Telegram.ChatID != cfg.Alerting.Telegram.ChatID ||
+			!reflect.DeepEqual(old.Alerting.Telegram.Commands, cfg.Alerting.Telegram.Commands)) {
+		settings = append(settings, "alerting.telegram.commands")
+	}
+	oldBot, bot := old.Alerting.Discord.Bot, cfg.Alerting.Discord.Bot
+	if oldBot.Listen != bot.Listen || bot.Listen != "" && (oldBot.Enable != bot.Enable || oldBot.BotToken != bot.BotToken ||
+		oldBot.ApplicationID != bot.ApplicationID || oldBot.PublicKey != bot.PublicKey || oldBot.GuildID != bot.GuildID ||
+		!reflect.DeepEqual(oldBot.AuthorizedUserIDs, bot.AuthorizedUserIDs)) {
+		settings = append(settings, "alerting.discord.bot interactions")
+	}
+	return settings
+}
+
+// WatchConfig reloads the config when one of its files changes or on SIGHUP.
+func WatchConfig(path string) {
+	signals := make(chan os.Signal, 1)
+	if len(reloadSignals) > 0 {
+		signal.Notify(signals, reloadSignals...)
+	}
+
+	version := configVersion(path)
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-signals:
+			log.Printf("Reloading config on signal")
+		case <-ticker.C:
+			newVersion := configVersion(path)
+			if newVersion == version {
+				continue
+			}
+			version = newVersion
+			log.Printf("Config %s changed, reloading", path)
+		}
+		ReloadConfig()
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !windows && !plan9
+
+package pkg
+
+import (
+	"os"
+	"syscall"
+)
+
+var reloadSignals = []os.Signal{syscall.SIGHUP}
@@ -0,0 +1,8 @@
+//go:build windows || plan9
+
+package pkg
+
+import "os"
+
+// There is no SIGHUP here; the config file is still watched.
+var reloadSignals []os.Signal
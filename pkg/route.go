@@ -0,0 +1,198 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Route sends the alerts of some chains/wallets that pass its filter to a set of notifiers.
+type Route struct {
+	Name      string   `yaml:"name"`
+	Chains    []string `yaml:"chains"`    // empty matches every chain
+	Wallets   []string `yaml:"wallets"`   // empty matches every wallet
+	Notifiers []string `yaml:"notifiers"` // e.g. discord, slack_bot; empty means all enabled
+	Filter    *Filter  `yaml:"filter"`
+	Digest    *Digest  `yaml:"digest"`
+}
+
+type route struct {
+	Route
+	notifiers []Notifier
+	digester  *digester
+}
+
+// Router dispatches alerts along the configured routes. Without routes every alert
+// goes to every enabled notifier.
+type Router struct {
+	routes []route
+}
+
+func NewRouter(routes []Route, notifiers []Notifier) *Router {
+	if len(routes) == 0 {
+		routes = []Route{{Name: "default"}}
+	}
+
+	byKey := make(map[string]Notifier, len(notifiers))
+	for _, notifier := range notifiers {
+		byKey[notifierKey(notifier)] = notifier
+	}
+
+	router := &Router{}
+	for _, r := range routes {
+		compiled := route{Route: r}
+		if len(r.Notifiers) == 0 {
+			compiled.notifiers = notifiers
+		}
+		for _, name := range r.Notifiers {
+			if notifier, ok := byKey[name]; ok {
+				compiled.notifiers = append(compiled.notifiers, notifier)
+			} else {
+				log.Printf("Route %s: notifier %s is not enabled", r.Name, name)
+			}
+		}
+		if r.Digest != nil {
+			var summaryNotifiers []SummaryNotifier
+			for _, notifier := range compiled.notifiers {
+				if summaryNotifier, ok := notifier.(SummaryNotifier); ok {
+					summaryNotifiers = append(summaryNotifiers, summaryNotifier)
+				}
+			}
+			compiled.digester = newDigester(fmt.Sprintf("%s digest", r.Name), r.Digest.interval, summaryNotifiers)
+		}
+		router.routes = append(router.routes, compiled)
+	}
+	return router
+}
+
+// Dispatch delivers the alert along every matching route and returns the history
+// status: delivered if any route accepted it, or queued if a notifier's rate limit held
+// it back, otherwise muted or filtered.
+func (r *Router) Dispatch(alertData AlertData) string {
+	status, queued := AlertFiltered, false
+	sent := make(map[Notifier]bool)
+	for _, route := range r.routes {
+		if !route.matches(alertData) {
+			continue
+		}
+		if !route.Filter.Match(alertData) {
+			log.Printf("Transaction %s filtered out by route %s", alertData.TxHash, route.Name)
+			continue
+		}
+		if window, _ := mutes.Match(alertData.ChainName, alertData.WalletAddress, route.Name); window != nil {
+			log.Printf("Route %s is muted by window %s, skipping transaction %s", route.Name, window.ID, alertData.TxHash)
+			if status != AlertDelivered {
+				status = AlertMuted
+			}
+			continue
+		}
+		status = AlertDelivered
+
+		digest := route.digester != nil && !route.Digest.bypasses(alertData)
+		if digest {
+			route.digester.Add(alertData)
+		}
+		for _, notifier := range route.notifiers {
+			// Notifiers that cannot render a digest still get every alert.
+			if _, ok := notifier.(SummaryNotifier); ok && digest {
+				continue
+			}
+			// Overlapping routes must not send the same alert twice.
+			if sent[notifier] {
+				continue
+			}
+			sent[notifier] = true
+			switch err := notifier.Notify(alertData); {
+			case errors.Is(err, errQueued):
+				log.Printf("Message to %s queued by its rate limit", notifier.Name())
+				queued = true
+			case err != nil:
+				log.Printf("Error sending message to %s: %v", notifier.Name(), err)
+			default:
+				log.Printf("Message sent to %s successfully", notifier.Name())
+			}
+		}
+	}
+	if status == AlertDelivered && queued {
+		return AlertQueued
+	}
+	return status
+}
+
+// followUpNotifier is implemented by notifiers that follow up on their earlier alerts,
+// such as the Slack bot reporting the acknowledgement of a transfer it posted.
+type followUpNotifier interface {
+	NotifyFollowUps(alertData AlertData) bool
+}
+
+// FollowUp hands a tx that only settles earlier transfers to the follow-up notifiers
+// of the routes of its chain and wallet. Other notifiers never see it.
+func (r *Router) FollowUp(alertData AlertData) {
+	sent := make(map[Notifier]bool)
+	for _, route := range r.routes {
+		if !route.matches(alertData) {
+			continue
+		}
+		for _, notifier := range route.notifiers {
+			// Follow-ups update earlier messages and bypass rate limits.
+			switch limited := notifier.(type) {
+			case *rateLimitedNotifier:
+				notifier = limited.Notifier
+			case rateLimitedSummaryNotifier:
+				notifier = limited.Notifier
+			}
+			followUp, ok := notifier.(followUpNotifier)
+			if !ok || sent[notifier] {
+				continue
+			}
+			sent[notifier] = true
+			followUp.NotifyFollowUps(alertData)
+		}
+	}
+}
+
+// Replace hands the state of the router it replaces over to r: digests of routes that
+// still exist with the same interval carry on, the others are sent right away.
+func (r *Router) Replace(old *Router) {
+	for _, oldRoute := range old.routes {
+		if oldRoute.digester == nil {
+			continue
+		}
+		for _, route := range r.routes {
+			if route.digester != nil && route.Name == oldRoute.Name && route.digester.interval == oldRoute.digester.interval {
+				route.digester.takeOver(oldRoute.digester)
+				break
+			}
+		}
+		oldRoute.digester.Stop()
+	}
+}
+
+func (r route) matches(alertData AlertData) bool {
+	if len(r.Chains) > 0 && !containsFold(r.Chains, alertData.ChainName) {
+		return false
+	}
+	if len(r.Wallets) > 0 && !toSet(r.Wallets)[alertData.WalletAddress] {
+		return false
+	}
+	return true
+}
+
+// knownNotifiers are the notifier names routes and rate limits may refer to.
+var knownNotifiers = toSet([]string{"discord", "discord_bot", "slack", "slack_bot", "telegram", "matrix",
+	"mattermost", "teams", "nats", "kafka", "redis", "file", "stdout", "syslog"})
+
+// notifierKey is the name used to reference a notifier in routes, e.g. "slack_bot".
+func notifierKey(notifier Notifier) string {
+	return strings.ToLower(strings.ReplaceAll(notifier.Name(), " ", "_"))
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -5,74 +5,112 @@ import (
 	"time"
 )
 
+const (
+	alertFetchAttempts = 5
+	alertFetchBackoff  = 2 * time.Second // doubled after every failed attempt
+)
+
 var alertChan = make(chan Alert) // Buffer size can be adjusted based on expected load
 type Alert struct {
-	ChainName string
-	TxHash    string
+	ChainName     string
+	WalletAddress string
+	TxHash        string
 }
 
-func NewAlert(chainName, txHash string) Alert {
+func NewAlert(chainName, walletAddress, txHash string) Alert {
 	return Alert{
-		ChainName: chainName,
-		TxHash:    txHash,
+		ChainName:     chainName,
+		WalletAddress: walletAddress,
+		TxHash:        txHash,
 	}
 }
-func ProcessAlerts(cfg *Config, alertChan <-chan Alert) {
+
+// ProcessAlerts handles every alert with the config and router in effect when it arrives.
+func ProcessAlerts(alertChan <-chan Alert) {
 	for alert := range alertChan {
-		AlertRun(cfg, alert.ChainName, alert.TxHash)
+		state := current.Load()
+		AlertRun(state.cfg, state.router, alert)
 	}
 }
 
-func AlertRun(cfg *Config, chainName string, txhash string) {
-	url := buildAPIURL(cfg.Chains[chainName].API, txhash)
+// AlertRun fetches the transaction of an alert and dispatches it. A failed fetch is
+// retried in the background with backoff, each time with the config and router in
+// effect then, and given up after alertFetchAttempts.
+func AlertRun(cfg *Config, router *Router, alert Alert) {
+	alertRun(cfg, router, alert, 1)
+}
 
-	apiData, err := fetchAPIData(url)
-	if err != nil {
-		log.Printf("Error fetching API data: %v", err)
-		go AlertRun(cfg, chainName, txhash)
+func alertRun(cfg *Config, router *Router, alert Alert, attempt int) {
+	if _, ok := cfg.Chains[alert.ChainName]; !ok {
+		log.Printf("Chain %s is no longer configured, dropping transaction %s", alert.ChainName, alert.TxHash)
 		return
 	}
-	var alerts AlertData
-
-	transformData(apiData, &alerts)
-	alerts.ChainName = chainName
-	alerts.ExplorerURL = cfg.Chains[chainName].Explorer
-
-	if cfg.Alerting.Discord.Enable {
-		if err := SendDiscordWebhook(cfg.Alerting.Discord.WebhookURL, alerts); err != nil {
-			log.Printf("Error sending message to Discord: %v", err)
-		} else {
-			log.Println("Message sent to Discord successfully")
+	alerts, err := buildAlertData(cfg, alert.ChainName, alert.WalletAddress, alert.TxHash)
+	if err != nil {
+		if attempt >= alertFetchAttempts {
+			log.Printf("Error fetching transaction %s on %s, giving up after %d attempts: %v", alert.TxHash, alert.ChainName, attempt, err)
+			return
 		}
+		backoff := alertFetchBackoff << (attempt - 1)
+		log.Printf("Error fetching transaction %s on %s: %v. Retrying in %s", alert.TxHash, alert.ChainName, err, backoff)
+		time.AfterFunc(backoff, func() {
+			state := current.Load()
+			alertRun(state.cfg, state.router, alert, attempt+1)
+		})
+		return
 	}
+	alerts.WalletLabel = addressBook.Label(alert.WalletAddress)
 
-	if cfg.Alerting.Slack.Enable {
-		if err := SendSlackWebhook(cfg.Alerting.Slack.WebhookURL, alerts); err != nil {
-			log.Printf("Error sending message to Slack: %v", err)
-		} else {
-			log.Println("Message sent to Slack successfully")
-		}
+	if window, until := mutes.Match(alert.ChainName, alert.WalletAddress, ""); window != nil {
+		log.Printf("Wallet %s on %s is muted by window %s until %s, skipping notifications", alert.WalletAddress, alert.ChainName, window.ID, until.Format(time.RFC3339))
+		history.Add(alerts, AlertMuted)
+		return
 	}
-
-	if cfg.Alerting.Telegram.Enable {
-		if err := SendTelegramMessage(cfg.Alerting.Telegram.BotToken, cfg.Alerting.Telegram.ChatID, alerts); err != nil {
-			log.Printf("Error sending message to Telegram: %v", err)
-		} else {
-			log.Println("Message sent to Telegram successfully")
-		}
+	if alerts.followUpOnly() {
+		log.Printf("Transaction %s settles transfers of wallet %s, sending follow-ups only", alerts.TxHash, alert.WalletAddress)
+		router.FollowUp(alerts)
+		return
+	}
+	if !cfg.WalletFilter(alert.ChainName, alert.WalletAddress).Match(alerts) {
+		log.Printf("Transaction %s filtered out by wallet %s filter", alerts.TxHash, alert.WalletAddress)
+		history.Add(alerts, AlertFiltered)
+		return
 	}
 
+	history.Add(alerts, router.Dispatch(alerts))
 }
 
-func Run(cfg *Config) {
+// buildAlertData fetches a transaction from the chain API and renders it as AlertData
+// for a monitored wallet, or for no wallet in particular when walletAddress is "".
+func buildAlertData(cfg *Config, chainName string, walletAddress string, txhash string) (AlertData, error) {
+	alerts := AlertData{WalletAddress: walletAddress}
 
-	for name, chain := range cfg.Chains {
-		for _, walletInfo := range chain.WalletInfo {
-			go SubscribeToNewBlocks(cfg, chain, name, walletInfo.WalletAddress)
-		}
+	apiData, err := fetchAPIData(buildAPIURL(cfg.Chains[chainName].API, txhash))
+	if err != nil {
+		return alerts, err
+	}
 
+	transformData(apiData, &alerts, cfg.Chains[chainName], cfg.Prices.book)
+	alerts.ChainName = chainName
+	alerts.ExplorerURL = cfg.Chains[chainName].Explorer
+	return alerts, nil
+}
+
+func Run(cfg *Config) {
+	start(cfg, nil)
+	go ProcessAlerts(alertChan)
+	if cfg.path != "" {
+		go WatchConfig(cfg.path)
+	}
+	if cfg.Alerting.Telegram.Commands.Enable {
+		go RunTelegramBot(cfg)
+	}
+	if cfg.Admin.Listen != "" {
+		go RunAdminAPI(cfg)
+	}
+	if cfg.Alerting.Discord.Bot.Enable && cfg.Alerting.Discord.Bot.Listen != "" {
+		go RunDiscordInteractions(cfg)
 	}
-	go ProcessAlerts(cfg, alertChan)
 
 	for {
 		time.Sleep(1 * time.Second)
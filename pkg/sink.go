@@ -0,0 +1,146 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// jsonLineSink writes every alert as a single JSON document followed by a newline.
+type jsonLineSink struct {
+	name   string
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer // nil for stdout
+}
+
+func (s *jsonLineSink) Name() string {
+	return s.name
+}
+
+func (s *jsonLineSink) Notify(alertData AlertData) error {
+	line, err := json.Marshal(NewTxEvent(alertData))
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(line, '\n'))
+	return err
+}
+
+func (s *jsonLineSink) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closer.Close()
+}
+
+func NewStdoutSink() Notifier {
+	return &jsonLineSink{name: "Stdout", w: os.Stdout}
+}
+
+func NewFileSink(path string, maxSizeMB int, maxBackups int) (Notifier, error) {
+	file, err := openRotatingFile(path, int64(maxSizeMB)*1024*1024, maxBackups)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonLineSink{name: "File", w: file, closer: file}, nil
+}
+
+// rotatingFile renames path to path.1 (shifting older backups up) once it grows past maxSize.
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func openRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) Close() error {
+	return r.file.Close()
+}
+
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	if r.maxBackups > 0 {
+		os.Remove(fmt.Sprintf("%s.%d", r.path, r.maxBackups))
+		for i := r.maxBackups - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+		}
+		if err := os.Rename(r.path, r.path+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Remove(r.path); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+// EnableSinks turns on local sinks given on the command line, e.g. "stdout,file:/var/log/tx.jsonl,syslog".
+func EnableSinks(cfg *Config, sinks string) error {
+	cfg.sinks = sinks
+	for _, sink := range strings.Split(sinks, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(sink), ":")
+		switch name {
+		case "":
+		case "stdout":
+			cfg.Alerting.Stdout.Enable = true
+		case "syslog":
+			cfg.Alerting.Syslog.Enable = true
+		case "file":
+			if arg == "" && cfg.Alerting.File.Path == "" {
+				return fmt.Errorf("sink %q needs a path, e.g. file:./alerts.jsonl", sink)
+			}
+			cfg.Alerting.File.Enable = true
+			if arg != "" {
+				cfg.Alerting.File.Path = arg
+			}
+		default:
+			return fmt.Errorf("unknown sink %q", sink)
+		}
+	}
+	return nil
+}
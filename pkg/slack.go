@@ -7,6 +7,12 @@ import (
 	"net/http"
 )
 
+// Slack allows at most 50 blocks per message and 10 fields per section.
+const (
+	slackMaxBlocks        = 50
+	slackMaxSectionFields = 10
+)
+
 type SlackWebhook struct {
 	Blocks []Block `json:"blocks"`
 }
@@ -16,11 +22,12 @@ type Field struct {
 	Short bool   `json:"short"`
 }
 type Block struct {
-	Type    string      `json:"type"`
-	Text    *BlockText  `json:"text,omitempty"`
-	Fields  []BlockText `json:"fields,omitempty"`
-	Divider bool        `json:"divider,omitempty"`
-	Ts      int64       `json:"ts,omitempty"`
+	Type     string      `json:"type"`
+	Text     *BlockText  `json:"text,omitempty"`
+	Fields   []BlockText `json:"fields,omitempty"`
+	Elements []BlockText `json:"elements,omitempty"`
+	Divider  bool        `json:"divider,omitempty"`
+	Ts       int64       `json:"ts,omitempty"`
 }
 
 type BlockText struct {
@@ -29,10 +36,36 @@ type BlockText struct {
 }
 
 func SendSlackWebhook(webhookURL string, alertData AlertData) error {
+	webhook := SlackWebhook{
+		Blocks: buildSlackBlocks(alertData),
+	}
+
+	jsonBytes, err := json.Marshal(webhook)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", webhookURL, bytes.NewBuffer(jsonBytes))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+func buildSlackBlocks(alertData AlertData) []Block {
 	var blocks []Block
 
 	// Title Block
-	titleText := fmt.Sprintf("*%s New Transaction*\n<%s%s|View on Explorer>", alertData.ChainName, alertData.ExplorerURL, alertData.TxHash)
+	titleText := fmt.Sprintf("*%s*\n<%s|View on Explorer>", alertData.heading(), alertData.TxURL())
 	blocks = append(blocks, Block{
 		Type: "section",
 		Text: &BlockText{Type: "mrkdwn", Text: titleText},
@@ -51,6 +84,9 @@ func SendSlackWebhook(webhookURL string, alertData AlertData) error {
 	})
 
 	heightText := fmt.Sprintf("Height: `%s`\nFees: `%s`\nMemo : `%s`", alertData.Height, alertData.Fees, alertData.Memo)
+	if paidBy := alertData.feePaidBy(); paidBy != "" {
+		heightText += fmt.Sprintf("\nFee Paid By: `%s`", paidBy)
+	}
 	blocks = append(blocks, Block{
 		Type: "section",
 		Text: &BlockText{Type: "mrkdwn", Text: heightText},
@@ -58,10 +94,10 @@ func SendSlackWebhook(webhookURL string, alertData AlertData) error {
 
 	// Divider Block
 
-	for _, detail := range alertData.MessageDetails {
+	for _, detail := range alertData.allMessages() {
 		// Detail Header Block
 		blocks = append(blocks, Block{Type: "divider"})
-		headerText := fmt.Sprintf("*#%d %s*", detail.Index, detail.Action)
+		headerText := fmt.Sprintf("*%s*", detail.Title())
 		blocks = append(blocks, Block{
 			Type: "section",
 			Text: &BlockText{Type: "mrkdwn", Text: headerText},
@@ -75,37 +111,40 @@ func SendSlackWebhook(webhookURL string, alertData AlertData) error {
 				fields = append(fields, BlockText{Type: "mrkdwn", Text: fieldText})
 			}
 		}
-		if len(fields) > 0 {
+		for len(fields) > 0 {
+			n := len(fields)
+			if n > slackMaxSectionFields {
+				n = slackMaxSectionFields
+			}
 			blocks = append(blocks, Block{
 				Type:   "section",
-				Fields: fields,
+				Fields: fields[:n],
 			})
+			fields = fields[n:]
 		}
 
 		// Divider Block after each detail
 	}
 
-	webhook := SlackWebhook{
-		Blocks: blocks,
-	}
-
-	jsonBytes, err := json.Marshal(webhook)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest("POST", webhookURL, bytes.NewBuffer(jsonBytes))
-	if err != nil {
-		return err
+	if len(blocks) > slackMaxBlocks {
+		blocks = append(blocks[:slackMaxBlocks-1], Block{
+			Type: "section",
+			Text: &BlockText{Type: "mrkdwn", Text: "_Remaining details truncated_"},
+		})
 	}
-	req.Header.Set("Content-Type", "application/json")
+	return blocks
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
+func SendSlackSummary(webhookURL string, summary Summary) error {
+	webhook := SlackWebhook{
+		Blocks: buildSlackSummaryBlocks(summary),
 	}
-	defer resp.Body.Close()
+	return postJSON("POST", webhookURL, webhook, nil)
+}
 
-	return nil
+func buildSlackSummaryBlocks(summary Summary) []Block {
+	return []Block{{
+		Type: "section",
+		Text: &BlockText{Type: "mrkdwn", Text: truncate(summary.Text("*", slackLink), 3000)},
+	}}
 }
@@ -0,0 +1,221 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	slackAPIURL = "https://slack.com/api/"
+
+	SlackFollowUpThread = "thread"
+	SlackFollowUpUpdate = "update"
+
+	// How long a posted MsgTransfer waits for its acknowledgement or timeout.
+	slackFollowUpRetention = 7 * 24 * time.Hour
+)
+
+type slackMessage struct {
+	Channel  string  `json:"channel"`
+	Text     string  `json:"text"`
+	Blocks   []Block `json:"blocks,omitempty"`
+	ThreadTs string  `json:"thread_ts,omitempty"`
+	Ts       string  `json:"ts,omitempty"`
+}
+
+type slackResponse struct {
+	OK      bool   `json:"ok"`
+	Error   string `json:"error"`
+	Channel string `json:"channel"`
+	Ts      string `json:"ts"`
+}
+
+// slackPost is a message posted for a MsgTransfer, kept until its packet settles.
+type slackPost struct {
+	channel  string
+	ts       string
+	blocks   []Block
+	postedAt time.Time
+}
+
+// slackBotNotifier posts with chat.postMessage to several channels and reports IBC
+// acknowledgements and timeouts as thread replies to (or edits of) the original transfer.
+type slackBotNotifier struct {
+	botToken string
+	channels []string
+	followUp string
+	client   *http.Client
+
+	mu        sync.Mutex
+	transfers map[string][]slackPost // IBC packet key -> posts of the MsgTransfer
+}
+
+func NewSlackBotNotifier(botToken string, channels []string, followUp string) Notifier {
+	if followUp != SlackFollowUpUpdate {
+		followUp = SlackFollowUpThread
+	}
+	return &slackBotNotifier{
+		botToken:  botToken,
+		channels:  channels,
+		followUp:  followUp,
+		client:    &http.Client{Timeout: 15 * time.Second},
+		transfers: make(map[string][]slackPost),
+	}
+}
+
+func (n *slackBotNotifier) Name() string {
+	return "Slack Bot"
+}
+
+func (n *slackBotNotifier) Notify(alertData AlertData) error {
+	if n.NotifyFollowUps(alertData) {
+		return nil
+	}
+
+	blocks := buildSlackBlocks(alertData)
+	text := fmt.Sprintf("%s %s", alertData.heading(), alertData.TxHash)
+
+	var errs []string
+	for _, channel := range n.channels {
+		resp, err := n.call("chat.postMessage", slackMessage{Channel: channel, Text: text, Blocks: blocks})
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", channel, err))
+			continue
+		}
+		n.rememberTransfers(alertData, slackPost{channel: resp.Channel, ts: resp.Ts, blocks: blocks, postedAt: time.Now()})
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("chat.postMessage failed for %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (n *slackBotNotifier) rememberTransfers(alertData AlertData, post slackPost) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for key, posts := range n.transfers {
+		if time.Since(posts[0].postedAt) > slackFollowUpRetention {
+			delete(n.transfers, key)
+		}
+	}
+	for _, packet := range alertData.IBCPackets {
+		if packet.Kind == IBCPacketSend {
+			key := packet.Key(alertData.ChainName)
+			n.transfers[key] = append(n.transfers[key], post)
+		}
+	}
+}
+
+// NotifyFollowUps reports acknowledgements and timeouts of earlier transfers. It returns
+// true if the alert is a relayer transaction settling known transfers, which then needs
+// no message of its own.
+func (n *slackBotNotifier) NotifyFollowUps(alertData AlertData) bool {
+	matched := 0
+	for _, packet := range alertData.IBCPackets {
+		if packet.Kind == IBCPacketSend {
+			continue
+		}
+
+		key := packet.Key(alertData.ChainName)
+		n.mu.Lock()
+		posts, ok := n.transfers[key]
+		delete(n.transfers, key)
+		n.mu.Unlock()
+		if !ok {
+			continue
+		}
+		matched++
+
+		text := slackFollowUpText(alertData, packet)
+		for _, post := range posts {
+			var err error
+			if n.followUp == SlackFollowUpUpdate {
+				blocks := append(append([]Block{}, post.blocks...), Block{
+					Type:     "context",
+					Elements: []BlockText{{Type: "mrkdwn", Text: text}},
+				})
+				_, err = n.call("chat.update", slackMessage{Channel: post.channel, Ts: post.ts, Text: text, Blocks: blocks})
+			} else {
+				_, err = n.call("chat.postMessage", slackMessage{Channel: post.channel, ThreadTs: post.ts, Text: text})
+			}
+			if err != nil {
+				log.Printf("Error sending IBC follow-up to Slack: %v", err)
+			}
+		}
+	}
+	return matched > 0 && len(alertData.MessageDetails) == countSettlingMessages(alertData)
+}
+
+func slackFollowUpText(alertData AlertData, packet IBCPacket) string {
+	link := fmt.Sprintf("<%s|%s>", alertData.TxURL(), shortHash(alertData.TxHash))
+	switch {
+	case packet.Kind == IBCPacketTimeout:
+		return fmt.Sprintf(":hourglass: IBC transfer timed out and was refunded (%s)", link)
+	case packet.Error != "":
+		return fmt.Sprintf(":x: IBC transfer failed on the counterparty and was refunded: `%s` (%s)", packet.Error, link)
+	default:
+		return fmt.Sprintf(":white_check_mark: IBC transfer acknowledged (%s)", link)
+	}
+}
+
+// countSettlingMessages counts the messages that only deliver acknowledgements,
+// timeouts or the client updates relayers bundle with them.
+func countSettlingMessages(alertData AlertData) int {
+	count := 0
+	for _, detail := range alertData.MessageDetails {
+		switch detail.Action {
+		case "IBC Acknowledgement", "IBC Timeout", "IBC Update Client":
+			count++
+		}
+	}
+	return count
+}
+
+func (n *slackBotNotifier) call(method string, message slackMessage) (*slackResponse, error) {
+	jsonBytes, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", slackAPIURL+method, bytes.NewBuffer(jsonBytes))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+n.botToken)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var slackResp slackResponse
+	if err := json.NewDecoder(resp.Body).Decode(&slackResp); err != nil {
+		return nil, err
+	}
+	if !slackResp.OK {
+		return nil, fmt.Errorf("%s: %s", method, slackResp.Error)
+	}
+	return &slackResp, nil
+}
+
+func (n *slackBotNotifier) NotifySummary(summary Summary) error {
+	var errs []string
+	for _, channel := range n.channels {
+		if _, err := n.call("chat.postMessage", slackMessage{Channel: channel, Text: summary.Title, Blocks: buildSlackSummaryBlocks(summary)}); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", channel, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("chat.postMessage failed for %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package pkg
+
+import (
+	"time"
+)
+
+// doNotModify marks description fields that MsgEditValidator leaves unchanged.
+const doNotModify = "[do-not-modify]"
+
+type ValidatorDescription struct {
+	Moniker         string `json:"moniker"`
+	Identity        string `json:"identity"`
+	Website         string `json:"website"`
+	SecurityContact string `json:"security_contact"`
+	Details         string `json:"details"`
+}
+
+type CommissionRates struct {
+	Rate          string `json:"rate"`
+	MaxRate       string `json:"max_rate"`
+	MaxChangeRate string `json:"max_change_rate"`
+}
+
+// populateStakingDetails adds what staking lifecycle messages carry beyond addresses
+// and amounts: when unbonding completes, and the settings of a validator.
+func populateStakingDetails(details *MessageDetail, message Message, apiData *Response, msgIndex int) {
+	switch message.Type {
+	case "/cosmos.staking.v1beta1.MsgUndelegate":
+		appendCompletionTime(details, findEventAttribute(apiData, msgIndex, "unbond", "completion_time"))
+	case "/cosmos.staking.v1beta1.MsgBeginRedelegate":
+		appendCompletionTime(details, findEventAttribute(apiData, msgIndex, "redelegate", "completion_time"))
+	case "/cosmos.staking.v1beta1.MsgCancelUnbondingDelegation":
+		appendIfNotNil(&details.Details, "Creation Height", message.CreationHeight)
+	case "/cosmos.staking.v1beta1.MsgCreateValidator", "/cosmos.staking.v1beta1.MsgEditValidator":
+		if description := message.Description; description != nil {
+			for _, field := range []struct{ key, value string }{
+				{"Moniker", description.Moniker},
+				{"Identity", description.Identity},
+				{"Website", description.Website},
+				{"Security Contact", description.SecurityContact},
+				{"Details", description.Details},
+			} {
+				if field.value != "" && field.value != doNotModify {
+					appendIfNotNil(&details.Details, field.key, &field.value)
+				}
+			}
+		}
+		if commission := message.Commission; commission != nil {
+			rate, maxRate, maxChangeRate := formatRate(commission.Rate), formatRate(commission.MaxRate), formatRate(commission.MaxChangeRate)
+			appendIfNotNil(&details.Details, "Commission Rate", &rate)
+			appendIfNotNil(&details.Details, "Max Commission Rate", &maxRate)
+			appendIfNotNil(&details.Details, "Max Commission Change", &maxChangeRate)
+		}
+		if message.CommissionRate != nil && *message.CommissionRate != "" {
+			rate := formatRate(*message.CommissionRate)
+			appendIfNotNil(&details.Details, "Commission Rate", &rate)
+		}
+		if message.MinSelfDelegation != nil && *message.MinSelfDelegation != "" {
+			appendIfNotNil(&details.Details, "Min Self Delegation", message.MinSelfDelegation)
+		}
+	}
+}
+
+// appendCompletionTime adds when an unbonding or redelegation completes, in UTC.
+func appendCompletionTime(details *MessageDetail, completionTime string) {
+	if completionTime == "" {
+		return
+	}
+	if parsed, err := time.Parse(time.RFC3339, completionTime); err == nil {
+		completionTime = parsed.UTC().Format("2006-01-02 15:04 MST")
+	}
+	appendIfNotNil(&details.Details, "Completion Time", &completionTime)
+}
+
+// formatRate renders a rate such as "0.050000000000000000" as "5%".
+func formatRate(rate string) string {
+	parsed, err := ParseDecimal(rate)
+	if err != nil {
+		return rate
+	}
+	return parsed.Shift(-2).String() + "%"
+}
@@ -0,0 +1,60 @@
+package pkg
+
+import (
+	"sync"
+	"time"
+)
+
+// ConnectionState is the WebSocket state of one wallet subscription.
+type ConnectionState struct {
+	Connected bool      `json:"connected"`
+	Since     time.Time `json:"since"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+type connectionRegistry struct {
+	mu     sync.Mutex
+	states map[string]map[string]ConnectionState
+}
+
+var connections = &connectionRegistry{states: make(map[string]map[string]ConnectionState)}
+
+func (r *connectionRegistry) Set(chainName string, walletAddress string, connected bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.states[chainName] == nil {
+		r.states[chainName] = make(map[string]ConnectionState)
+	}
+	state := ConnectionState{Connected: connected, Since: time.Now()}
+	if err != nil {
+		state.LastError = err.Error()
+	}
+	r.states[chainName][walletAddress] = state
+}
+
+// Remove forgets a subscription that was stopped.
+func (r *connectionRegistry) Remove(chainName string, walletAddress string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.states[chainName], walletAddress)
+	if len(r.states[chainName]) == 0 {
+		delete(r.states, chainName)
+	}
+}
+
+// Snapshot returns a copy of the state of every subscription, keyed by chain and wallet.
+func (r *connectionRegistry) Snapshot() map[string]map[string]ConnectionState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshot := make(map[string]map[string]ConnectionState, len(r.states))
+	for chainName, wallets := range r.states {
+		snapshot[chainName] = make(map[string]ConnectionState, len(wallets))
+		for walletAddress, state := range wallets {
+			snapshot[chainName][walletAddress] = state
+		}
+	}
+	return snapshot
+}
@@ -0,0 +1,166 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SummaryNotifier is implemented by notifiers that can deliver a Summary, such as
+// digests, instead of one message per alert. Others always receive every alert.
+type SummaryNotifier interface {
+	Notifier
+	NotifySummary(summary Summary) error
+}
+
+// Summary aggregates several alerts into one message.
+type Summary struct {
+	Title    string         `json:"title"`
+	Start    time.Time      `json:"start"`
+	End      time.Time      `json:"end"`
+	Count    int            `json:"count"`
+	Actions  map[string]int `json:"actions"`
+	Wallets  map[string]int `json:"wallets"`
+	Amounts  []Coin         `json:"amounts,omitempty"`
+	Fees     []Coin         `json:"fees,omitempty"`
+	Failures []SummaryTx    `json:"failures,omitempty"`
+}
+
+type SummaryTx struct {
+	ChainName string `json:"chain_name"`
+	TxHash    string `json:"tx_hash"`
+	URL       string `json:"url"`
+	Error     string `json:"error"`
+}
+
+func NewSummary(title string, start time.Time, end time.Time, alerts []AlertData) Summary {
+	summary := Summary{
+		Title:   title,
+		Start:   start,
+		End:     end,
+		Count:   len(alerts),
+		Actions: make(map[string]int),
+		Wallets: make(map[string]int),
+	}
+
+	amounts := make(map[string]Coin)
+	fees := make(map[string]Coin)
+	for _, alertData := range alerts {
+		if alertData.WalletLabel != "" {
+			summary.Wallets[alertData.WalletLabel]++
+		} else if alertData.WalletAddress != "" {
+			summary.Wallets[shortAddress(alertData.WalletAddress)]++
+		}
+		for _, detail := range alertData.allMessages() {
+			summary.Actions[detail.Action]++
+			for _, coin := range detail.Amounts {
+				addCoin(amounts, coin)
+			}
+		}
+		for _, coin := range alertData.FeeAmounts {
+			addCoin(fees, coin)
+		}
+		if alertData.Error != "" {
+			summary.Failures = append(summary.Failures, SummaryTx{
+				ChainName: alertData.ChainName,
+				TxHash:    alertData.TxHash,
+				URL:       alertData.TxURL(),
+				Error:     alertData.Error,
+			})
+		}
+	}
+	summary.Amounts = sortedCoins(amounts)
+	summary.Fees = sortedCoins(fees)
+	return summary
+}
+
+// addCoin adds coin to the total of its denom. The total has a fiat value only when
+// every coin added to it has one.
+func addCoin(totals map[string]Coin, coin Coin) {
+	total, seen := totals[coin.Denom]
+	if !seen {
+		totals[coin.Denom] = Coin{Denom: coin.Denom, Amount: coin.Amount, Value: coin.Value}
+		return
+	}
+	total.Amount = total.Amount.Add(coin.Amount)
+	if total.Value != nil && coin.Value != nil {
+		total.Value = &FiatValue{Amount: total.Value.Amount.Add(coin.Value.Amount), Currency: total.Value.Currency}
+	} else {
+		total.Value = nil
+	}
+	totals[coin.Denom] = total
+}
+
+func sortedCoins(totals map[string]Coin) []Coin {
+	var coins []Coin
+	for _, denom := range sortedKeys(totals) {
+		coins = append(coins, totals[denom])
+	}
+	return coins
+}
+
+// Text renders the summary for chat platforms, given how they write bold text and links.
+func (s Summary) Text(bold string, link func(text string, url string) string) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("%s%s%s\n", bold, s.Title, bold))
+	if !s.Start.IsZero() {
+		text.WriteString(fmt.Sprintf("%s – %s\n", s.Start.UTC().Format("2006-01-02 15:04"), s.End.UTC().Format("2006-01-02 15:04 MST")))
+	}
+	text.WriteString(fmt.Sprintf("%d transactions\n", s.Count))
+
+	if len(s.Wallets) > 0 {
+		text.WriteString(fmt.Sprintf("%sWallets:%s %s\n", bold, bold, countList(s.Wallets)))
+	}
+	if len(s.Actions) > 0 {
+		text.WriteString(fmt.Sprintf("%sActions:%s %s\n", bold, bold, countList(s.Actions)))
+	}
+	if len(s.Amounts) > 0 {
+		text.WriteString(fmt.Sprintf("%sAmounts:%s %s\n", bold, bold, formatCoins(s.Amounts)))
+	}
+	if len(s.Fees) > 0 {
+		text.WriteString(fmt.Sprintf("%sFees:%s %s\n", bold, bold, formatCoins(s.Fees)))
+	}
+	if len(s.Failures) > 0 {
+		text.WriteString(fmt.Sprintf("%sFailures (%d):%s\n", bold, len(s.Failures), bold))
+		for _, failure := range s.Failures {
+			text.WriteString(fmt.Sprintf("• %s %s\n", link(shortHash(failure.TxHash), failure.URL), truncateLine(failure.Error, 120)))
+		}
+	}
+	return text.String()
+}
+
+// countList renders counts as "Send ×3, Delegate ×1", the most frequent first.
+func countList(counts map[string]int) string {
+	keys := sortedKeys(counts)
+	sort.SliceStable(keys, func(i, j int) bool { return counts[keys[i]] > counts[keys[j]] })
+	var parts []string
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s ×%d", key, counts[key]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// truncateLine shortens s to one line of at most max bytes.
+func truncateLine(s string, max int) string {
+	s, _, _ = strings.Cut(s, "\n")
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && (s[max]&0xC0) == 0x80 {
+		max--
+	}
+	return s[:max] + "…"
+}
+
+func markdownLink(text string, url string) string {
+	return fmt.Sprintf("[%s](%s)", text, url)
+}
+
+func slackLink(text string, url string) string {
+	return fmt.Sprintf("<%s|%s>", url, text)
+}
+
+func plainLink(text string, url string) string {
+	return fmt.Sprintf("%s (%s)", text, url)
+}
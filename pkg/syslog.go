@@ -0,0 +1,40 @@
+//go:build !windows && !plan9
+
+package pkg
+
+import (
+	"encoding/json"
+	"log/syslog"
+)
+
+type syslogSink struct {
+	writer *syslog.Writer
+}
+
+// NewSyslogSink logs alerts as JSON to the local syslog daemon, failed transactions at LOG_ERR.
+func NewSyslogSink(tag string) (Notifier, error) {
+	writer, err := syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogSink{writer: writer}, nil
+}
+
+func (s *syslogSink) Name() string {
+	return "Syslog"
+}
+
+func (s *syslogSink) Notify(alertData AlertData) error {
+	line, err := json.Marshal(NewTxEvent(alertData))
+	if err != nil {
+		return err
+	}
+	if alertData.Error != "" {
+		return s.writer.Err(string(line))
+	}
+	return s.writer.Info(string(line))
+}
+
+func (s *syslogSink) Close() error {
+	return s.writer.Close()
+}
@@ -0,0 +1,9 @@
+//go:build windows || plan9
+
+package pkg
+
+import "errors"
+
+func NewSyslogSink(tag string) (Notifier, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
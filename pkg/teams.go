@@ -0,0 +1,131 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Teams rejects webhook payloads above 28 KB.
+const teamsMaxPayloadSize = 27000
+
+type TeamsWebhook struct {
+	Type        string            `json:"type"`
+	Attachments []TeamsAttachment `json:"attachments"`
+}
+
+type TeamsAttachment struct {
+	ContentType string       `json:"contentType"`
+	Content     AdaptiveCard `json:"content"`
+}
+
+type AdaptiveCard struct {
+	Schema  string               `json:"$schema"`
+	Type    string               `json:"type"`
+	Version string               `json:"version"`
+	Body    []AdaptiveCardItem   `json:"body"`
+	Actions []AdaptiveCardAction `json:"actions,omitempty"`
+}
+
+type AdaptiveCardItem struct {
+	Type      string         `json:"type"`
+	Text      string         `json:"text,omitempty"`
+	Weight    string         `json:"weight,omitempty"`
+	Size      string         `json:"size,omitempty"`
+	Color     string         `json:"color,omitempty"`
+	Wrap      bool           `json:"wrap,omitempty"`
+	Spacing   string         `json:"spacing,omitempty"`
+	Facts     []AdaptiveFact `json:"facts,omitempty"`
+	Separator bool           `json:"separator,omitempty"`
+}
+
+type AdaptiveFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type AdaptiveCardAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+func SendTeamsWebhook(webhookURL string, alertData AlertData) error {
+	body := []AdaptiveCardItem{
+		{Type: "TextBlock", Text: alertData.heading(), Weight: "Bolder", Size: "Medium", Wrap: true},
+	}
+	if alertData.Error != "" {
+		body = append(body, AdaptiveCardItem{Type: "TextBlock", Text: fmt.Sprintf("Error: %s", alertData.Error), Color: "Attention", Wrap: true})
+	}
+	facts := []AdaptiveFact{
+		{Title: "Transaction", Value: alertData.TxHash},
+		{Title: "Height", Value: alertData.Height},
+		{Title: "Fees", Value: alertData.Fees},
+	}
+	if paidBy := alertData.feePaidBy(); paidBy != "" {
+		facts = append(facts, AdaptiveFact{Title: "Fee Paid By", Value: paidBy})
+	}
+	facts = append(facts, AdaptiveFact{Title: "Memo", Value: alertData.Memo})
+	body = append(body, AdaptiveCardItem{Type: "FactSet", Facts: facts})
+
+	webhook := newTeamsWebhook(body, alertData.TxURL())
+	for _, detail := range alertData.allMessages() {
+		facts := []AdaptiveFact{}
+		for _, d := range detail.Details {
+			for k, v := range d {
+				facts = append(facts, AdaptiveFact{Title: k, Value: v})
+			}
+		}
+		items := []AdaptiveCardItem{
+			{Type: "TextBlock", Text: detail.Title(), Weight: "Bolder", Wrap: true, Separator: true},
+		}
+		if len(facts) > 0 {
+			items = append(items, AdaptiveCardItem{Type: "FactSet", Facts: facts})
+		}
+
+		// Stop adding message details once the card would exceed the webhook limit.
+		card := &webhook.Attachments[0].Content
+		card.Body = append(card.Body, items...)
+		if jsonBytes, err := json.Marshal(webhook); err == nil && len(jsonBytes) > teamsMaxPayloadSize {
+			card.Body = append(card.Body[:len(card.Body)-len(items)], AdaptiveCardItem{
+				Type: "TextBlock", Text: "…(remaining messages truncated)", Wrap: true, Separator: true,
+			})
+			break
+		}
+	}
+
+	return postJSON("POST", webhookURL, webhook, nil)
+}
+
+func newTeamsWebhook(body []AdaptiveCardItem, explorerURL string) TeamsWebhook {
+	return TeamsWebhook{
+		Type: "message",
+		Attachments: []TeamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content: AdaptiveCard{
+				Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+				Type:    "AdaptiveCard",
+				Version: "1.4",
+				Body:    body,
+				Actions: []AdaptiveCardAction{{Type: "Action.OpenUrl", Title: "View on Explorer", URL: explorerURL}},
+			},
+		}},
+	}
+}
+
+func SendTeamsSummary(webhookURL string, summary Summary) error {
+	webhook := TeamsWebhook{
+		Type: "message",
+		Attachments: []TeamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content: AdaptiveCard{
+				Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+				Type:    "AdaptiveCard",
+				Version: "1.4",
+				Body: []AdaptiveCardItem{
+					{Type: "TextBlock", Text: truncate(summary.Text("**", markdownLink), teamsMaxPayloadSize/2), Wrap: true},
+				},
+			},
+		}},
+	}
+	return postJSON("POST", webhookURL, webhook, nil)
+}
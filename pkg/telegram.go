@@ -14,23 +14,34 @@ type TelegramMessage struct {
 }
 
 func SendTelegramMessage(botToken string, chatID string, alertData AlertData) error {
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", botToken)
+	return sendTelegramText(botToken, chatID, formatTelegramMessage(alertData))
+}
 
+func formatTelegramMessage(alertData AlertData) string {
 	var messageText string
-	messageText += fmt.Sprintf("*%s New Transaction*\n[View on Explorer](%s%s)\n", alertData.ChainName, alertData.ExplorerURL, alertData.TxHash)
+	messageText += fmt.Sprintf("*%s*\n[View on Explorer](%s)\n", alertData.heading(), alertData.TxURL())
 	if alertData.Error != "" {
 		messageText += fmt.Sprintf("Error: ```%s```\n", alertData.Error)
 	}
 	messageText += fmt.Sprintf("Transaction: `%s`\n", alertData.TxHash)
-	messageText += fmt.Sprintf("Height: `%s`\nFees: `%s`\nMemo: `%s`", alertData.Height, alertData.Fees, alertData.Memo)
-	for _, detail := range alertData.MessageDetails {
-		messageText += fmt.Sprintf("\n*#%d %s*\n", detail.Index, detail.Action)
+	messageText += fmt.Sprintf("Height: `%s`\nFees: `%s`\n", alertData.Height, alertData.Fees)
+	if paidBy := alertData.feePaidBy(); paidBy != "" {
+		messageText += fmt.Sprintf("Fee Paid By: `%s`\n", paidBy)
+	}
+	messageText += fmt.Sprintf("Memo: `%s`", alertData.Memo)
+	for _, detail := range alertData.allMessages() {
+		messageText += fmt.Sprintf("\n*%s*\n", detail.Title())
 		for _, d := range detail.Details {
 			for k, v := range d {
 				messageText += fmt.Sprintf("*%s:* `%s`\n", k, v)
 			}
 		}
 	}
+	return messageText
+}
+
+func sendTelegramText(botToken string, chatID string, messageText string) error {
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", botToken)
 
 	telegramMessage := TelegramMessage{
 		ChatID:    chatID,
@@ -58,3 +69,7 @@ func SendTelegramMessage(botToken string, chatID string, alertData AlertData) er
 
 	return nil
 }
+
+func SendTelegramSummary(botToken string, chatID string, summary Summary) error {
+	return sendTelegramText(botToken, chatID, truncate(summary.Text("*", markdownLink), 4096))
+}
@@ -0,0 +1,92 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const telegramPollTimeout = 30 // seconds
+
+var telegramMarkup = chatMarkup{bold: "*", renderAlert: formatTelegramMessage}
+
+type telegramUpdate struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+		Text string `json:"text"`
+	} `json:"message"`
+}
+
+type telegramUpdatesResponse struct {
+	OK          bool             `json:"ok"`
+	Description string           `json:"description"`
+	Result      []telegramUpdate `json:"result"`
+}
+
+// RunTelegramBot long-polls getUpdates and answers commands from authorized chats.
+func RunTelegramBot(cfg *Config) {
+	botToken := cfg.Alerting.Telegram.BotToken
+	authorized := make(map[string]bool)
+	for _, chatID := range cfg.Alerting.Telegram.Commands.AuthorizedChatIDs {
+		authorized[chatID] = true
+	}
+	if len(authorized) == 0 {
+		authorized[cfg.Alerting.Telegram.ChatID] = true
+	}
+
+	client := &http.Client{Timeout: (telegramPollTimeout + 10) * time.Second}
+	var offset int64
+	for {
+		updates, err := getTelegramUpdates(client, botToken, offset)
+		if err != nil {
+			log.Printf("Error polling Telegram updates: %v", err)
+			time.Sleep(5 * time.Second)
+			continue
+		}
+
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			if update.Message == nil || !strings.HasPrefix(update.Message.Text, "/") {
+				continue
+			}
+			chatID := strconv.FormatInt(update.Message.Chat.ID, 10)
+			if !authorized[chatID] {
+				log.Printf("Ignoring Telegram command from unauthorized chat %s", chatID)
+				continue
+			}
+
+			args := strings.Fields(update.Message.Text)
+			command, _, _ := strings.Cut(strings.TrimPrefix(args[0], "/"), "@") // "/status@my_bot" in group chats
+			reply := runCommand(currentConfig(), telegramMarkup, command, args[1:])
+			if err := sendTelegramText(botToken, chatID, reply); err != nil {
+				log.Printf("Error replying to Telegram command: %v", err)
+			}
+		}
+	}
+}
+
+func getTelegramUpdates(client *http.Client, botToken string, offset int64) ([]telegramUpdate, error) {
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/getUpdates?timeout=%d&offset=%d&allowed_updates=%%5B%%22message%%22%%5D",
+		botToken, telegramPollTimeout, offset)
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var updates telegramUpdatesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&updates); err != nil {
+		return nil, err
+	}
+	if !updates.OK {
+		return nil, fmt.Errorf("getUpdates failed: %s", updates.Description)
+	}
+	return updates.Result, nil
+}
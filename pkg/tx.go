@@ -7,9 +7,7 @@ import (
 	"io"
 	"log"
 	"net/http"
-	"regexp"
 	"strconv"
-	"strings"
 )
 
 func UnmarshalResponse(data []byte) (Response, error) {
@@ -103,6 +101,45 @@ type Message struct {
 	// Data               *string     `json:"data,omitempty"`
 	Data   *json.RawMessage `json:"data,omitempty"`
 	Packet *PacketData      `json:"packet,omitempty"`
+	// Staking
+	ValidatorSrcAddress *string               `json:"validator_src_address,omitempty"`
+	ValidatorDstAddress *string               `json:"validator_dst_address,omitempty"`
+	CreationHeight      *string               `json:"creation_height,omitempty"`
+	Description         *ValidatorDescription `json:"description,omitempty"`
+	Commission          *CommissionRates      `json:"commission,omitempty"`
+	CommissionRate      *string               `json:"commission_rate,omitempty"`
+	MinSelfDelegation   *string               `json:"min_self_delegation,omitempty"`
+	Value               *Amount               `json:"value,omitempty"`
+	// Authz
+	Granter    *string     `json:"granter,omitempty"`
+	Grantee    *string     `json:"grantee,omitempty"`
+	Msgs       []Message   `json:"msgs,omitempty"`
+	Grant      *AuthzGrant `json:"grant,omitempty"`
+	MsgTypeURL *string     `json:"msg_type_url,omitempty"`
+	// CosmWasm
+	Contract     *string         `json:"contract,omitempty"`
+	Msg          json.RawMessage `json:"msg,omitempty"`
+	Funds        []Amount        `json:"funds,omitempty"`
+	Admin        *string         `json:"admin,omitempty"`
+	CodeID       *string         `json:"code_id,omitempty"`
+	Label        *string         `json:"label,omitempty"`
+	WasmByteCode *string         `json:"wasm_byte_code,omitempty"`
+	// Gov
+	Options          []WeightedVoteOption `json:"options,omitempty"`
+	Depositor        *string              `json:"depositor,omitempty"`
+	Proposer         *string              `json:"proposer,omitempty"`
+	InitialDeposit   []Amount             `json:"initial_deposit,omitempty"`
+	Content          *ProposalContent     `json:"content,omitempty"` // v1beta1
+	Title            *string              `json:"title,omitempty"`   // v1
+	Summary          *string              `json:"summary,omitempty"` // v1
+	Expedited        bool                 `json:"expedited,omitempty"`
+	ProposalMessages []struct {
+		Type string `json:"@type"`
+	} `json:"messages,omitempty"` // v1
+	// Bank and feegrant
+	Inputs    []BankIO      `json:"inputs,omitempty"`
+	Outputs   []BankIO      `json:"outputs,omitempty"`
+	Allowance *FeeAllowance `json:"allowance,omitempty"`
 }
 type PacketData struct {
 	PacketSequence     *string `json:"sequence,omitempty"`
@@ -166,14 +203,15 @@ func (m *Message) UnmarshalJSON(data []byte) error {
 	}
 
 	switch m.Type {
-	case "/cosmos.bank.v1beta1.MsgSend":
+	case "/cosmos.bank.v1beta1.MsgSend", "/cosmos.gov.v1beta1.MsgDeposit", "/cosmos.gov.v1.MsgDeposit":
 		var amounts []Amount
 		if err := json.Unmarshal(aux.Amount, &amounts); err != nil {
 			return err
 		}
 		m.Amount = amounts
 
-	case "/cosmos.staking.v1beta1.MsgDelegate":
+	case "/cosmos.staking.v1beta1.MsgDelegate", "/cosmos.staking.v1beta1.MsgUndelegate",
+		"/cosmos.staking.v1beta1.MsgBeginRedelegate", "/cosmos.staking.v1beta1.MsgCancelUnbondingDelegation":
 		var amount Amount
 		if err := json.Unmarshal(aux.Amount, &amount); err != nil {
 			return err
@@ -228,20 +266,93 @@ func fetchAPIData(url string) (*Response, error) {
 }
 
 type AlertData struct {
-	TxHash         string
-	Height         string
-	Timestamp      string
-	ChainName      string
-	ExplorerURL    string
-	MessageDetails []MessageDetail
-	Fees           string
-	Memo           string
-	Error          string
+	TxHash         string          `json:"tx_hash"`
+	Height         string          `json:"height"`
+	Timestamp      string          `json:"timestamp"`
+	ChainName      string          `json:"chain_name"`
+	WalletAddress  string          `json:"wallet_address"`
+	WalletLabel    string          `json:"wallet_label,omitempty"`
+	ExplorerURL    string          `json:"explorer_url"`
+	MessageDetails []MessageDetail `json:"message_details"`
+	Fees           string          `json:"fees"`
+	FeeAmounts     []Coin          `json:"fee_amounts,omitempty"`
+	FeePayer       string          `json:"fee_payer,omitempty"`   // the tx's explicit fee payer; "" means the first signer
+	FeeGranter     string          `json:"fee_granter,omitempty"` // who paid the fee through a fee grant
+	Memo           string          `json:"memo"`
+	Error          string          `json:"error,omitempty"`
+	IBCPackets     []IBCPacket     `json:"ibc_packets,omitempty"`
+
+	transferSenders map[string]bool // senders of the tx's transfer events, as matched by the wallet query
 }
 type MessageDetail struct {
-	Index   int
-	Action  string
-	Details []map[string]string
+	Index     int                 `json:"index"`
+	Type      string              `json:"type"`
+	Action    string              `json:"action"`
+	Details   []map[string]string `json:"details"`
+	Amounts   []Coin              `json:"amounts,omitempty"`
+	Addresses []string            `json:"addresses,omitempty"`
+	// Messages executed by an authz MsgExec.
+	Messages []MessageDetail `json:"messages,omitempty"`
+
+	number string   // "1", or "1.2" for the second message nested in the first
+	payers []string // the addresses its amounts leave, see messagePayers
+}
+
+// Title is "#1 Send", or "#1.2 Delegate" for the second message executed by the first.
+func (d MessageDetail) Title() string {
+	if d.number == "" {
+		return fmt.Sprintf("#%d %s", d.Index, d.Action)
+	}
+	return fmt.Sprintf("#%s %s", d.number, d.Action)
+}
+
+// feePaidBy is who paid the fee when the tx names a payer or granter, e.g. "Sponsor (fee
+// grant to Treasury)", or "" when it was left to the first signer.
+func (a AlertData) feePaidBy() string {
+	switch {
+	case a.FeeGranter != "" && a.FeePayer != "":
+		return fmt.Sprintf("%s (fee grant to %s)", addressBook.Display(a.FeeGranter), addressBook.Display(a.FeePayer))
+	case a.FeeGranter != "":
+		return addressBook.Display(a.FeeGranter) + " (fee grant)"
+	default:
+		return addressBook.Display(a.FeePayer)
+	}
+}
+
+// heading is "Kava New Transaction", followed by the wallet's label when it has one:
+// "Kava New Transaction · Treasury".
+func (a AlertData) heading() string {
+	if a.WalletLabel == "" {
+		return a.ChainName + " New Transaction"
+	}
+	return fmt.Sprintf("%s New Transaction · %s", a.ChainName, a.WalletLabel)
+}
+
+// allMessages lists the messages of the tx with the messages nested in each right after it.
+func (a AlertData) allMessages() []MessageDetail {
+	return flattenMessages(a.MessageDetails)
+}
+
+func flattenMessages(details []MessageDetail) []MessageDetail {
+	var messages []MessageDetail
+	for _, detail := range details {
+		messages = append(messages, detail)
+		messages = append(messages, flattenMessages(detail.Messages)...)
+	}
+	return messages
+}
+
+// Coin is an amount in display units, e.g. 1.5 atom. An IBC voucher keeps its denom
+// on the chain and the channels it came through.
+type Coin struct {
+	Denom    string  `json:"denom"`
+	Amount   Decimal `json:"amount"`
+	IBCDenom string  `json:"ibc_denom,omitempty"` // e.g. ibc/27394FB0…
+	IBCPath  string  `json:"ibc_path,omitempty"`  // e.g. transfer/channel-0
+	// Value is set when a price source knows the coin.
+	Value *FiatValue `json:"value,omitempty"`
+
+	coinGeckoID string
 }
 
 func appendIfNotNil(details *[]map[string]string, key string, value *string) {
@@ -250,7 +361,14 @@ func appendIfNotNil(details *[]map[string]string, key string, value *string) {
 	}
 }
 
-func transformData(apiData *Response, alerts *AlertData) {
+// appendAddressIfNotNil is appendIfNotNil for addresses, showing address book names.
+func appendAddressIfNotNil(details *[]map[string]string, key string, value *string) {
+	if value != nil {
+		*details = append(*details, map[string]string{key: addressBook.Display(*value)})
+	}
+}
+
+func transformData(apiData *Response, alerts *AlertData, chain ChainConfig, prices *priceBook) {
 	if apiData == nil {
 		log.Println("apiData is nil")
 		return
@@ -264,41 +382,109 @@ func transformData(apiData *Response, alerts *AlertData) {
 	alerts.Height = apiData.TxResponse.Height
 	alerts.TxHash = apiData.TxResponse.Txhash
 	alerts.Memo = apiData.Tx.Body.Memo
+	alerts.IBCPackets = extractIBCPackets(apiData)
+	alerts.transferSenders = transferSenders(apiData)
 
 	// Extract fees
-	if len(apiData.Tx.AuthInfo.Fee.Amount) > 0 {
-		amount := extractNumber(apiData.Tx.AuthInfo.Fee.Amount[0].Amount) / 1000000
-		denom := extractDenom(apiData.Tx.AuthInfo.Fee.Amount[0].Denom)
-		alerts.Fees = fmt.Sprintf("%f %s", amount, denom)
+	alerts.FeeAmounts = prices.value(chain.displayCoins(apiData.Tx.AuthInfo.Fee.Amount))
+	if len(alerts.FeeAmounts) > 0 {
+		alerts.Fees = formatCoins(alerts.FeeAmounts)
 	} else {
 		alerts.Fees = "0"
 	}
+	alerts.FeePayer = apiData.Tx.AuthInfo.Fee.Payer
+	alerts.FeeGranter = apiData.Tx.AuthInfo.Fee.Granter
 
 	// Message processing
+	decoder := messageDecoder{apiData: apiData, wallet: alerts.WalletAddress, chain: chain, prices: prices}
 	for i, message := range apiData.Tx.Body.Messages {
-		// var messageDetail MessageDetail
-		// messageDetail.Index = i + 1
-		messageDetail := MessageDetail{
-			Index:   i + 1,
-			Details: make([]map[string]string, 0),
-		}
+		alerts.MessageDetails = append(alerts.MessageDetails, decoder.decode(message, i, i+1, strconv.Itoa(i+1), make(map[string]int)))
+	}
+}
 
-		// Depending on whether logs or events are available, choose the appropriate function
-		var amount float64
-		var denom string
-		eventType := getEventType(message.Type)
+// messageDecoder renders the messages of one tx as MessageDetails.
+type messageDecoder struct {
+	apiData *Response
+	wallet  string // the monitored wallet, or "" when fetched on demand
+	chain   ChainConfig
+	prices  *priceBook
+}
+
+// decode renders the message at msgIndex of the tx, or one nested in it by an authz
+// MsgExec. Nested messages share the events of the message at msgIndex; seen counts
+// the events of each type already taken by earlier ones.
+func (d messageDecoder) decode(message Message, msgIndex int, index int, number string, seen map[string]int) MessageDetail {
+	messageDetail := MessageDetail{
+		Index:   index,
+		Type:    message.Type,
+		Details: make([]map[string]string, 0),
+		number:  number,
+		payers:  messagePayers(message),
+	}
 
-		if len(apiData.TxResponse.Logs) > 0 {
-			amount, denom = extractAmountFromLogs(apiData.TxResponse.Logs, i, eventType)
-		} else {
-			amount, denom = extractAmountFromEvents(apiData.TxResponse.Events, eventType)
+	// Depending on whether logs or events are available, choose the appropriate function
+	var amounts []Amount
+	if eventType := getEventType(message.Type); eventType != "" {
+		values := findEventAttributes(d.apiData, msgIndex, eventType, "amount")
+		if occurrence := seen[eventType]; occurrence < len(values) {
+			var err error
+			amounts, err = parseCoins(values[occurrence])
+			if err != nil {
+				log.Printf("Error parsing %s amount: %v", eventType, err)
+			}
 		}
+		seen[eventType]++
+	}
+	if len(amounts) == 0 {
+		amounts = extractAmountsFromMessage(message)
+	}
+
+	// Populate message details based on the type
+	messageDetail.Action = getMessageAction(message.Type)
+	populateMessageDetails(&messageDetail, message, d.prices.value(d.chain.displayCoins(amounts)))
+	populateStakingDetails(&messageDetail, message, d.apiData, msgIndex)
+	populateAuthzDetails(&messageDetail, message, d.chain)
+	populateFeegrantDetails(&messageDetail, message, d.chain)
+	d.populateBankDetails(&messageDetail, message)
+	d.populateWasmDetails(&messageDetail, message, msgIndex)
+	d.populateGovDetails(&messageDetail, message, msgIndex)
+	for i, inner := range message.Msgs {
+		messageDetail.Messages = append(messageDetail.Messages, d.decode(inner, msgIndex, i+1, fmt.Sprintf("%s.%d", number, i+1), seen))
+	}
+	return messageDetail
+}
 
-		// Populate message details based on the type
-		messageDetail.Action = getMessageAction(message.Type)
-		populateMessageDetails(&messageDetail, message, amount, denom)
-		alerts.MessageDetails = append(alerts.MessageDetails, messageDetail)
+// messagePayers returns the addresses a message's amounts leave: the sender of a send,
+// IBC transfer, multi-send or contract call, the delegator of a delegation and the
+// depositor or proposer of a deposit. Other amounts, such as rewards, are received.
+func messagePayers(message Message) []string {
+	var payers []*string
+	switch message.Type {
+	case "/cosmos.bank.v1beta1.MsgSend":
+		payers = []*string{message.FromAddress}
+	case "/cosmos.bank.v1beta1.MsgMultiSend":
+		var addresses []string
+		for _, input := range message.Inputs {
+			addresses = append(addresses, input.Address)
+		}
+		return addresses
+	case "/ibc.applications.transfer.v1.MsgTransfer", "/cosmwasm.wasm.v1.MsgExecuteContract",
+		"/cosmwasm.wasm.v1.MsgInstantiateContract", "/cosmwasm.wasm.v1.MsgInstantiateContract2":
+		payers = []*string{message.Sender}
+	case "/cosmos.staking.v1beta1.MsgDelegate", "/cosmos.staking.v1beta1.MsgCreateValidator":
+		payers = []*string{message.DelegatorAddress}
+	case "/cosmos.gov.v1beta1.MsgDeposit", "/cosmos.gov.v1.MsgDeposit":
+		payers = []*string{message.Depositor}
+	case "/cosmos.gov.v1beta1.MsgSubmitProposal", "/cosmos.gov.v1.MsgSubmitProposal":
+		payers = []*string{message.Proposer}
 	}
+	var addresses []string
+	for _, payer := range payers {
+		if payer != nil && *payer != "" {
+			addresses = append(addresses, *payer)
+		}
+	}
+	return addresses
 }
 
 func getEventType(messageType string) string {
@@ -322,44 +508,104 @@ func getMessageAction(messageType string) string {
 		return "Get Commission"
 	case "/cosmos.staking.v1beta1.MsgDelegate":
 		return "Delegate"
+	case "/cosmos.staking.v1beta1.MsgUndelegate":
+		return "Undelegate"
+	case "/cosmos.staking.v1beta1.MsgBeginRedelegate":
+		return "Redelegate"
+	case "/cosmos.staking.v1beta1.MsgCancelUnbondingDelegation":
+		return "Cancel Unbonding"
+	case "/cosmos.staking.v1beta1.MsgCreateValidator":
+		return "Create Validator"
+	case "/cosmos.staking.v1beta1.MsgEditValidator":
+		return "Edit Validator"
+	case "/cosmos.authz.v1beta1.MsgExec":
+		return "Authz Exec"
+	case "/cosmos.authz.v1beta1.MsgGrant":
+		return "Authz Grant"
+	case "/cosmos.authz.v1beta1.MsgRevoke":
+		return "Authz Revoke"
+	case "/cosmwasm.wasm.v1.MsgExecuteContract":
+		return "Execute Contract"
+	case "/cosmwasm.wasm.v1.MsgInstantiateContract", "/cosmwasm.wasm.v1.MsgInstantiateContract2":
+		return "Instantiate Contract"
+	case "/cosmwasm.wasm.v1.MsgMigrateContract":
+		return "Migrate Contract"
+	case "/cosmwasm.wasm.v1.MsgStoreCode":
+		return "Store Code"
 	case "/ibc.applications.transfer.v1.MsgTransfer":
 		return "IBC Transfer"
-	case "/cosmos.gov.v1beta1.MsgVote":
+	case "/cosmos.gov.v1beta1.MsgVote", "/cosmos.gov.v1.MsgVote":
 		return "Vote"
+	case "/cosmos.gov.v1beta1.MsgVoteWeighted", "/cosmos.gov.v1.MsgVoteWeighted":
+		return "Weighted Vote"
+	case "/cosmos.gov.v1beta1.MsgSubmitProposal", "/cosmos.gov.v1.MsgSubmitProposal":
+		return "Submit Proposal"
+	case "/cosmos.gov.v1beta1.MsgDeposit", "/cosmos.gov.v1.MsgDeposit":
+		return "Deposit"
 	case "/cosmos.bank.v1beta1.MsgSend":
 		return "Send"
+	case "/cosmos.bank.v1beta1.MsgMultiSend":
+		return "Multi Send"
+	case "/cosmos.feegrant.v1beta1.MsgGrantAllowance":
+		return "Grant Fee Allowance"
+	case "/cosmos.feegrant.v1beta1.MsgRevokeAllowance":
+		return "Revoke Fee Allowance"
 	case "/ibc.core.client.v1.MsgUpdateClient":
 		return "IBC Update Client"
 	case "/ibc.core.channel.v1.MsgRecvPacket":
 		return "IBC Received"
 	case "/ibc.core.channel.v1.MsgAcknowledgement":
 		return "IBC Acknowledgement"
+	case "/ibc.core.channel.v1.MsgTimeout", "/ibc.core.channel.v1.MsgTimeoutOnClose":
+		return "IBC Timeout"
 	default:
 		return messageType
 	}
 }
 
-func populateMessageDetails(details *MessageDetail, message Message, amount float64, denom string) {
-	appendIfNotNil(&details.Details, "Delegator Address", message.DelegatorAddress)
-	appendIfNotNil(&details.Details, "Validator Address", message.ValidatorAddress)
-	appendIfNotNil(&details.Details, "From Address", message.FromAddress)
-	appendIfNotNil(&details.Details, "To Address", message.ToAddress)
-	appendIfNotNil(&details.Details, "Sender", message.Sender)
-	appendIfNotNil(&details.Details, "Receiver", message.Receiver)
+func populateMessageDetails(details *MessageDetail, message Message, coins []Coin) {
+	appendAddressIfNotNil(&details.Details, "Delegator Address", message.DelegatorAddress)
+	appendAddressIfNotNil(&details.Details, "Validator Address", message.ValidatorAddress)
+	appendAddressIfNotNil(&details.Details, "Source Validator", message.ValidatorSrcAddress)
+	appendAddressIfNotNil(&details.Details, "Destination Validator", message.ValidatorDstAddress)
+	appendAddressIfNotNil(&details.Details, "From Address", message.FromAddress)
+	appendAddressIfNotNil(&details.Details, "To Address", message.ToAddress)
+	appendAddressIfNotNil(&details.Details, "Sender", message.Sender)
+	appendAddressIfNotNil(&details.Details, "Receiver", message.Receiver)
 	appendIfNotNil(&details.Details, "Source Channel", message.SourceChannel)
 	appendIfNotNil(&details.Details, "Port", message.SourcePort)
 	appendIfNotNil(&details.Details, "Proposal Id", message.ProposalId)
-	appendIfNotNil(&details.Details, "Voter", message.Voter)
-	appendIfNotNil(&details.Details, "Option", message.Option)
-	appendIfNotNil(&details.Details, "Signer", message.Signer)
+	appendAddressIfNotNil(&details.Details, "Voter", message.Voter)
+	if message.Option != nil {
+		option := voteOptionName(*message.Option)
+		appendIfNotNil(&details.Details, "Option", &option)
+	}
+	appendAddressIfNotNil(&details.Details, "Depositor", message.Depositor)
+	appendAddressIfNotNil(&details.Details, "Proposer", message.Proposer)
+	appendAddressIfNotNil(&details.Details, "Signer", message.Signer)
+	appendAddressIfNotNil(&details.Details, "Granter", message.Granter)
+	appendAddressIfNotNil(&details.Details, "Grantee", message.Grantee)
 	appendIfNotNil(&details.Details, "Client ID", message.ClientID)
 	appendIfNotNil(&details.Details, "Source Port", message.SourcePort)
 	appendIfNotNil(&details.Details, "Timeout Timestamp", message.TimeoutTimestamp)
 	appendIfNotNil(&details.Details, "Sequence", message.PacketSequence)
 	appendIfNotNil(&details.Details, "Destination Port", message.DestinationPort)
-	if amount != 0 {
-		Amount := fmt.Sprintf("%f %s", amount, denom)
+	if len(coins) > 0 {
+		Amount := formatCoins(coins)
 		appendIfNotNil(&details.Details, "Amount", &Amount)
+		details.Amounts = append(details.Amounts, coins...)
+	}
+	for _, coin := range coins {
+		if coin.IBCPath != "" {
+			origin := fmt.Sprintf("%s via %s (%s)", coin.Denom, coin.IBCPath, shortDenom(coin.IBCDenom))
+			appendIfNotNil(&details.Details, "IBC Origin", &origin)
+		}
+	}
+	for _, address := range []*string{message.DelegatorAddress, message.ValidatorAddress, message.ValidatorSrcAddress, message.ValidatorDstAddress, message.FromAddress,
+		message.ToAddress, message.Sender, message.Receiver, message.Voter, message.Signer, message.Granter, message.Grantee} {
+		if address != nil && *address != "" {
+			details.Addresses = append(details.Addresses, *address)
+		}
 	}
 
 	if packet := message.Packet; packet != nil {
@@ -376,6 +622,9 @@ func populateMessageDetails(details *MessageDetail, message Message, amount floa
 				json.Unmarshal(decodedData, &packetData)
 				for key, value := range packetData {
 					valueStr := fmt.Sprintf("%v", value) // Convert interface{} to string
+					if key == "sender" || key == "receiver" {
+						valueStr = addressBook.Display(valueStr)
+					}
 					appendIfNotNil(&details.Details, key, &valueStr)
 				}
 			}
@@ -383,81 +632,19 @@ func populateMessageDetails(details *MessageDetail, message Message, amount floa
 	}
 }
 
-func extractNumber(str string) float64 {
-	reg, err := regexp.Compile("^[0-9]+")
-	if err != nil {
-		log.Println(err)
-	}
-	// Find the first match which is a continuous sequence of digits at the beginning
-	match := reg.FindString(str)
-	if match == "" {
-		log.Println("No leading number found in string:", str)
-		return 0
-	}
-
-	number, err := strconv.ParseFloat(match, 64)
-	if err != nil {
-		log.Println(str)
-		log.Println("Error converting to integer:", err)
-		return 0
-	}
-	return number
-}
-
-// Extracts the alphabetic part from the string.
-func extractDenom(str string) string {
-	reg, err := regexp.Compile("[^a-zA-Z]+")
-	if err != nil {
-		log.Println(err)
-	}
-	denom := reg.ReplaceAllString(str, "")
-
-	// Remove 'u' prefix if it exists
-	if strings.HasPrefix(denom, "u") {
-		denom = strings.TrimPrefix(denom, "u")
-	}
-
-	return denom
-}
-func extractAmountFromEvents(events []Event, eventType string) (float64, string) {
-	if len(events) == 0 {
-		log.Println("No events found")
-		return 0, ""
-	}
-
-	for _, event := range events {
-		if event.Type == eventType {
-			for _, attr := range event.Attributes {
-				if attr.Key == "amount" {
-					amount := extractNumber(attr.Value) / 1000000
-					denom := extractDenom(attr.Value)
-					return amount, denom
-				}
-			}
-		}
+// extractAmountsFromMessage reads the amount of messages that carry it themselves (MsgSend, MsgTransfer).
+func extractAmountsFromMessage(message Message) []Amount {
+	switch amount := message.Amount.(type) {
+	case []Amount:
+		return amount
+	case Amount:
+		return []Amount{amount}
 	}
-	return 0, ""
-}
-func extractAmountFromLogs(logs []Log, msgIndex int, eventType string) (float64, string) {
-	if len(logs) == 0 {
-		log.Println("No logs found")
-		return 0, ""
+	if message.Value != nil {
+		return []Amount{*message.Value}
 	}
-
-	for _, log := range logs {
-		if log.MsgIndex == int64(msgIndex) {
-			for _, event := range log.Events {
-				if event.Type == eventType {
-					for _, attr := range event.Attributes {
-						if attr.Key == "amount" {
-							amount := extractNumber(attr.Value) / 1000000
-							denom := extractDenom(attr.Value)
-							return amount, denom
-						}
-					}
-				}
-			}
-		}
+	if message.Token != nil {
+		return []Amount{{Denom: message.Token.Denom, Amount: message.Token.Amount}}
 	}
-	return 0, ""
+	return nil
 }
@@ -0,0 +1,140 @@
+package pkg
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const restakeMessages = `{"@type":"/cosmos.authz.v1beta1.MsgExec","grantee":"cosmos1bot","msgs":[
+	{"@type":"/cosmos.distribution.v1beta1.MsgWithdrawDelegatorReward","delegator_address":"cosmos1a","validator_address":"cosmosvaloper1x"},
+	{"@type":"/cosmos.distribution.v1beta1.MsgWithdrawDelegatorReward","delegator_address":"cosmos1a","validator_address":"cosmosvaloper1y"}]}`
+
+func TestDecodeNestedMessageAmounts(t *testing.T) {
+	tests := []struct {
+		name       string
+		txResponse string
+	}{
+		{
+			// SDK 0.47 and older: events of one type merged in the message's log.
+			name: "logs",
+			txResponse: `{"logs":[{"msg_index":0,"events":[{"type":"withdraw_rewards","attributes":[
+				{"key":"amount","value":"1000000uatom"},{"key":"validator","value":"cosmosvaloper1x"},
+				{"key":"amount","value":"2500000uatom"},{"key":"validator","value":"cosmosvaloper1y"}]}]}]}`,
+		},
+		{
+			name: "events",
+			txResponse: `{"events":[
+				{"type":"withdraw_rewards","attributes":[{"key":"amount","value":"1000000uatom"},{"key":"msg_index","value":"0"}]},
+				{"type":"withdraw_rewards","attributes":[{"key":"amount","value":"2500000uatom"},{"key":"msg_index","value":"0"}]}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var apiData Response
+			raw := `{"tx":{"body":{"messages":[` + restakeMessages + `]}},"tx_response":` + tt.txResponse + `}`
+			if err := json.Unmarshal([]byte(raw), &apiData); err != nil {
+				t.Fatal(err)
+			}
+			var alertData AlertData
+			transformData(&apiData, &alertData, ChainConfig{}, nil)
+
+			nested := alertData.MessageDetails[0].Messages
+			if len(nested) != 2 {
+				t.Fatalf("got %d nested messages, want 2", len(nested))
+			}
+			for i, want := range []string{"1", "2.5"} {
+				if got := formatCoins(nested[i].Amounts); got != want+" atom" {
+					t.Errorf("%s amount = %q, want %s atom", nested[i].Title(), got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFollowUpOnly(t *testing.T) {
+	acknowledged := []IBCPacket{{Kind: IBCPacketAcknowledgement}}
+	tests := []struct {
+		name  string
+		alert AlertData
+		want  bool
+	}{
+		{"relayer acknowledgement", AlertData{WalletAddress: "cosmos1a", IBCPackets: acknowledged}, true},
+		{"relayer timeout", AlertData{WalletAddress: "cosmos1a", IBCPackets: []IBCPacket{{Kind: IBCPacketTimeout}}}, true},
+		{"wallet sends too", AlertData{WalletAddress: "cosmos1a", IBCPackets: acknowledged, transferSenders: map[string]bool{"cosmos1a": true}}, false},
+		{"transfer", AlertData{WalletAddress: "cosmos1a", IBCPackets: []IBCPacket{{Kind: IBCPacketSend}}}, false},
+		{"no packets", AlertData{WalletAddress: "cosmos1a"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.alert.followUpOnly(); got != tt.want {
+			t.Errorf("%s: followUpOnly() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMultiSendAttribution(t *testing.T) {
+	const message = `{"@type":"/cosmos.bank.v1beta1.MsgMultiSend",
+		"inputs":[{"address":"cosmos1a","coins":[{"denom":"uatom","amount":"3000000"}]}],
+		"outputs":[{"address":"cosmos1b","coins":[{"denom":"uatom","amount":"1000000"}]},
+			{"address":"cosmos1c","coins":[{"denom":"uatom","amount":"2000000"}]}]}`
+	tests := []struct {
+		wallet    string
+		amount    string
+		addresses []string
+	}{
+		{"cosmos1a", "3 atom", []string{"cosmos1b", "cosmos1c"}},
+		{"cosmos1c", "2 atom", []string{"cosmos1a"}},
+		{"", "3 atom", []string{"cosmos1a", "cosmos1b", "cosmos1c"}},
+	}
+	for _, tt := range tests {
+		var apiData Response
+		if err := json.Unmarshal([]byte(`{"tx":{"body":{"messages":[`+message+`]}}}`), &apiData); err != nil {
+			t.Fatal(err)
+		}
+		alertData := AlertData{WalletAddress: tt.wallet}
+		transformData(&apiData, &alertData, ChainConfig{}, nil)
+
+		detail := alertData.MessageDetails[0]
+		if got := formatCoins(detail.Amounts); got != tt.amount {
+			t.Errorf("wallet %q: amount = %q, want %q", tt.wallet, got, tt.amount)
+		}
+		if got := strings.Join(detail.Addresses, ","); got != strings.Join(tt.addresses, ",") {
+			t.Errorf("wallet %q: addresses = %s, want %v", tt.wallet, got, tt.addresses)
+		}
+	}
+}
+
+func TestFindEventsSkipsTxLevelEvents(t *testing.T) {
+	tests := []struct {
+		name   string
+		events string
+		want   []string
+	}{
+		{
+			// SDK 0.50: the ante handler's fee transfer carries no msg_index.
+			name: "tagged",
+			events: `[{"type":"transfer","attributes":[{"key":"amount","value":"5000uatom"}]},
+				{"type":"transfer","attributes":[{"key":"amount","value":"1000000uatom"},{"key":"msg_index","value":"0"}]},
+				{"type":"transfer","attributes":[{"key":"amount","value":"2000000uatom"},{"key":"msg_index","value":"1"}]}]`,
+			want: []string{"1000000uatom"},
+		},
+		{
+			name: "untagged",
+			events: `[{"type":"transfer","attributes":[{"key":"amount","value":"5000uatom"}]},
+				{"type":"transfer","attributes":[{"key":"amount","value":"1000000uatom"}]}]`,
+			want: []string{"5000uatom", "1000000uatom"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var apiData Response
+			if err := json.Unmarshal([]byte(`{"tx_response":{"events":`+tt.events+`}}`), &apiData); err != nil {
+				t.Fatal(err)
+			}
+			got := findEventAttributes(&apiData, 0, "transfer", "amount")
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("amounts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
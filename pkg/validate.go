@@ -0,0 +1,329 @@
+package pkg
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigProblem is one error found in the config files, printed as "file:line: path: message".
+type ConfigProblem struct {
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+func (p ConfigProblem) String() string {
+	var b strings.Builder
+	if p.File != "" {
+		b.WriteString(p.File + ":")
+		if p.Line > 0 {
+			fmt.Fprintf(&b, "%d:", p.Line)
+		}
+		b.WriteString(" ")
+	} else if p.Line > 0 {
+		fmt.Fprintf(&b, "line %d: ", p.Line)
+	}
+	if p.Path != "" {
+		b.WriteString(p.Path + ": ")
+	}
+	b.WriteString(p.Message)
+	return b.String()
+}
+
+// validator collects problems, locating each one in the parsed YAML documents.
+type validator struct {
+	root     *yaml.Node
+	files    map[*yaml.Node]string // file each node was read from
+	problems []ConfigProblem
+}
+
+// nodef records a problem at a node.
+func (v *validator) nodef(node *yaml.Node, path []interface{}, format string, args ...interface{}) {
+	problem := ConfigProblem{Path: pathString(path), Message: fmt.Sprintf(format, args...)}
+	if node != nil {
+		problem.File, problem.Line = v.files[node], node.Line
+	}
+	v.problems = append(v.problems, problem)
+}
+
+// addf records a problem at a path of mapping keys (string) and sequence indexes (int).
+// The line is that of the deepest node of the path present in the file.
+func (v *validator) addf(path []interface{}, format string, args ...interface{}) {
+	var found *yaml.Node
+	node := v.root
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	for _, key := range path {
+		switch key := key.(type) {
+		case int:
+			if node != nil && node.Kind == yaml.SequenceNode && key < len(node.Content) {
+				node = node.Content[key]
+			} else {
+				node = nil
+			}
+		case string:
+			node = mappingValue(node, key)
+		}
+		if node == nil {
+			break
+		}
+		found = node
+	}
+	v.nodef(found, path, format, args...)
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// at builds a path for addf.
+func at(path ...interface{}) []interface{} {
+	return path
+}
+
+// checkFields reports keys of the document that do not match any field of t, suggesting
+// the field a key differs from only by case or underscores (e.g. wallet_info).
+func (v *validator) checkFields(node *yaml.Node, t reflect.Type, path []interface{}) {
+	if node == nil {
+		return
+	}
+	if node.Kind == yaml.DocumentNode || node.Kind == yaml.AliasNode {
+		for _, child := range node.Content {
+			v.checkFields(child, t, path)
+		}
+		if node.Alias != nil {
+			v.checkFields(node.Alias, t, path)
+		}
+		return
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			if key == "<<" { // merge key
+				continue
+			}
+			field, ok := fields[key]
+			if !ok {
+				message := fmt.Sprintf("unknown field %q", key)
+				for name := range fields {
+					if normalizeKey(name) == normalizeKey(key) {
+						message += fmt.Sprintf(", did you mean %q?", name)
+					}
+				}
+				v.nodef(node.Content[i], path, "%s", message)
+				continue
+			}
+			v.checkFields(node.Content[i+1], field.Type, append(path[:len(path):len(path)], key))
+		}
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v.checkFields(node.Content[i+1], t.Elem(), append(path[:len(path):len(path)], node.Content[i].Value))
+		}
+	case reflect.Slice, reflect.Array:
+		if node.Kind != yaml.SequenceNode {
+			return
+		}
+		for i, item := range node.Content {
+			v.checkFields(item, t.Elem(), append(path[:len(path):len(path)], i))
+		}
+	}
+}
+
+// yamlFields maps the YAML keys of a struct to its fields, like the decoder does.
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+}
+
+func pathString(path []interface{}) string {
+	var b strings.Builder
+	for _, key := range path {
+		switch key := key.(type) {
+		case int:
+			fmt.Fprintf(&b, "[%d]", key)
+		case string:
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(key)
+		}
+	}
+	return b.String()
+}
+
+// validate checks what the YAML decoder cannot: URLs, addresses, credentials of enabled
+// notifiers and the names used by routes and rate limits.
+func (c *Config) validate(v *validator) {
+	a := &c.Alerting
+	required := func(enabled bool, path []interface{}, values map[string]string) {
+		if !enabled {
+			return
+		}
+		for _, key := range sortedKeys(values) {
+			if strings.TrimSpace(values[key]) == "" {
+				v.addf(append(path[:len(path):len(path)], key), "required when enabled")
+			}
+		}
+	}
+	required(a.Slack.Enable, at("alerting", "slack"), map[string]string{"webhook_url": a.Slack.WebhookURL})
+	required(a.Slack.Bot.Enable, at("alerting", "slack", "bot"), map[string]string{"bot_token": a.Slack.Bot.BotToken})
+	required(a.Telegram.Enable, at("alerting", "telegram"), map[string]string{"bot_token": a.Telegram.BotToken, "chat_id": a.Telegram.ChatID})
+	required(a.Discord.Enable, at("alerting", "discord"), map[string]string{"webhook_url": a.Discord.WebhookURL})
+	required(a.Discord.Bot.Enable, at("alerting", "discord", "bot"), map[string]string{"bot_token": a.Discord.Bot.BotToken, "channel_id": a.Discord.Bot.ChannelID})
+	required(a.Discord.Bot.Enable && a.Discord.Bot.Listen != "", at("alerting", "discord", "bot"), map[string]string{"application_id": a.Discord.Bot.ApplicationID, "public_key": a.Discord.Bot.PublicKey, "authorized_user_ids": strings.Join(a.Discord.Bot.AuthorizedUserIDs, "")})
+	required(a.Matrix.Enable, at("alerting", "matrix"), map[string]string{"homeserver": a.Matrix.Homeserver, "access_token": a.Matrix.AccessToken, "room_id": a.Matrix.RoomID})
+	required(a.Mattermost.Enable, at("alerting", "mattermost"), map[string]string{"webhook_url": a.Mattermost.WebhookURL})
+	required(a.Teams.Enable, at("alerting", "teams"), map[string]string{"webhook_url": a.Teams.WebhookURL})
+	required(a.Nats.Enable, at("alerting", "nats"), map[string]string{"url": a.Nats.URL, "subject": a.Nats.Subject})
+	required(a.Kafka.Enable, at("alerting", "kafka"), map[string]string{"brokers": strings.Join(a.Kafka.Brokers, ""), "topic": a.Kafka.Topic})
+	required(a.Redis.Enable, at("alerting", "redis"), map[string]string{"addr": a.Redis.Addr, "stream": a.Redis.Stream})
+	required(a.File.Enable, at("alerting", "file"), map[string]string{"path": a.File.Path})
+	if a.Slack.Bot.Enable && len(a.Slack.Bot.Channels) == 0 {
+		v.addf(at("alerting", "slack", "bot", "channels"), "required when enabled")
+	}
+	switch a.Slack.Bot.FollowUp {
+	case "", "thread", "update":
+	default:
+		v.addf(at("alerting", "slack", "bot", "follow_up"), "must be thread or update, not %q", a.Slack.Bot.FollowUp)
+	}
+
+	checkURL := func(path []interface{}, value string, schemes ...string) {
+		if value == "" {
+			return
+		}
+		u, err := url.Parse(value)
+		if err != nil {
+			v.addf(path, "invalid URL: %v", err)
+			return
+		}
+		if u.Host == "" || !containsFold(schemes, u.Scheme) {
+			v.addf(path, "invalid URL %q: expected %s://host", value, strings.Join(schemes, " or "))
+		}
+	}
+	checkURL(at("alerting", "slack", "webhook_url"), a.Slack.WebhookURL, "https")
+	checkURL(at("alerting", "discord", "webhook_url"), a.Discord.WebhookURL, "https")
+	checkURL(at("alerting", "matrix", "homeserver"), a.Matrix.Homeserver, "https", "http")
+	checkURL(at("alerting", "mattermost", "webhook_url"), a.Mattermost.WebhookURL, "https", "http")
+	checkURL(at("alerting", "teams", "webhook_url"), a.Teams.WebhookURL, "https")
+	for _, server := range strings.Split(a.Nats.URL, ",") {
+		checkURL(at("alerting", "nats", "url"), strings.TrimSpace(server), "nats", "tls", "ws", "wss")
+	}
+	checkURL(at("prices", "coingecko", "url"), c.Prices.CoinGecko.URL, "https", "http")
+
+	for i, route := range a.Routes {
+		for j, name := range route.Notifiers {
+			if !knownNotifiers[name] {
+				v.addf(at("alerting", "routes", i, "notifiers", j), "unknown notifier %q", name)
+			}
+		}
+		for j, wallet := range route.Wallets {
+			if _, _, err := decodeBech32(wallet); err != nil {
+				v.addf(at("alerting", "routes", i, "wallets", j), "invalid address %q: %v", wallet, err)
+			}
+		}
+	}
+	for _, name := range sortedKeys(a.RateLimits) {
+		if !knownNotifiers[name] {
+			v.addf(at("alerting", "rate_limits", name), "unknown notifier %q", name)
+		}
+	}
+	for i, window := range a.Mutes {
+		for j, wallet := range window.Wallets {
+			if _, _, err := decodeBech32(wallet); err != nil {
+				v.addf(at("alerting", "mutes", i, "wallets", j), "invalid address %q: %v", wallet, err)
+			}
+		}
+	}
+	for _, address := range sortedKeys(c.AddressBook.Addresses) {
+		if _, _, err := decodeBech32(address); err != nil {
+			v.addf(at("address_book", "addresses", address), "invalid address %q: %v", address, err)
+		}
+	}
+
+	if c.Admin.Listen != "" && c.Admin.Token == "" && !isLoopbackListen(c.Admin.Listen) {
+		v.addf(at("admin", "token"), "required when admin.listen %q is not a loopback address", c.Admin.Listen)
+	}
+
+	if len(c.Chains) == 0 {
+		v.addf(at("chains"), "no chains configured")
+	}
+	for _, chainName := range sortedKeys(c.Chains) {
+		chain := c.Chains[chainName]
+		if chain.RPC == "" {
+			v.addf(at("chains", chainName, "rpc"), "required")
+		}
+		if chain.API == "" {
+			v.addf(at("chains", chainName, "api"), "required")
+		}
+		checkURL(at("chains", chainName, "rpc"), chain.RPC, "https", "http", "wss", "ws")
+		checkURL(at("chains", chainName, "api"), chain.API, "https", "http")
+		checkURL(at("chains", chainName, "explorerURL"), chain.Explorer, "https", "http")
+
+		prefix := chain.Prefix
+		for i, walletInfo := range chain.WalletInfo {
+			hrp, _, err := decodeBech32(walletInfo.WalletAddress)
+			switch {
+			case err != nil:
+				v.addf(at("chains", chainName, "wallet_Info", i, "wallet_address"), "invalid address %q: %v", walletInfo.WalletAddress, err)
+			case prefix == "":
+				prefix = hrp
+			case hrp != prefix && prefix == chain.Prefix:
+				v.addf(at("chains", chainName, "wallet_Info", i, "wallet_address"), "prefix %q differs from the chain's bech32_prefix %q", hrp, prefix)
+			case hrp != prefix:
+				v.addf(at("chains", chainName, "wallet_Info", i, "wallet_address"), "prefix %q differs from %q of the chain's other wallets", hrp, prefix)
+			}
+		}
+		for _, address := range sortedKeys(chain.Contracts) {
+			if _, _, err := decodeBech32(address); err != nil {
+				v.addf(at("chains", chainName, "contracts", address), "invalid address %q: %v", address, err)
+			}
+			if decoder := chain.Contracts[address].Decoder; decoder != "" && contractDecoders[decoder] == nil {
+				v.addf(at("chains", chainName, "contracts", address, "decoder"), "unknown decoder %q, expected one of: %s", decoder, contractDecoderNames())
+			}
+		}
+	}
+}
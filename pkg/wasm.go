@@ -0,0 +1,216 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	wasmPayloadMax  = 500 // bytes shown of the message sent to a contract
+	wasmEventMax    = 300 // bytes shown of one wasm event
+	wasmEventsShown = 5
+)
+
+// ContractConfig describes a known contract of a chain.
+type ContractConfig struct {
+	Label    string `yaml:"label"`    // shown instead of the address, like the address book
+	Decoder  string `yaml:"decoder"`  // renders its execute messages, e.g. "cw20"
+	Symbol   string `yaml:"symbol"`   // token symbol for cw20
+	Decimals int    `yaml:"decimals"` // token decimals for cw20
+}
+
+// contractDecoders render the execute messages of known contract types. They return
+// false for messages they do not know, which are then shown as plain JSON.
+var contractDecoders = map[string]func(d messageDecoder, details *MessageDetail, contract ContractConfig, method string, payload json.RawMessage) bool{
+	"cw20": decodeCW20,
+}
+
+// populateWasmDetails adds the contract, the message sent to it and the wasm events
+// of CosmWasm messages.
+func (d messageDecoder) populateWasmDetails(details *MessageDetail, message Message, msgIndex int) {
+	switch message.Type {
+	case "/cosmwasm.wasm.v1.MsgExecuteContract":
+		appendAddressIfNotNil(&details.Details, "Contract", message.Contract)
+		payload := wasmPayload(message.Msg)
+		method := wasmMethod(payload)
+		contract := d.chain.Contracts[stringValue(message.Contract)]
+		decoder := contractDecoders[contract.Decoder]
+		if decoder == nil || !decoder(d, details, contract, method, payload) {
+			appendIfNotNil(&details.Details, "Method", &method)
+			appendPayload(details, payload)
+		}
+	case "/cosmwasm.wasm.v1.MsgInstantiateContract", "/cosmwasm.wasm.v1.MsgInstantiateContract2":
+		appendIfNotNil(&details.Details, "Code ID", message.CodeID)
+		appendIfNotNil(&details.Details, "Label", message.Label)
+		appendAddressIfNotNil(&details.Details, "Admin", message.Admin)
+		for _, address := range findEventAttributes(d.apiData, msgIndex, "instantiate", "_contract_address") {
+			appendAddressIfNotNil(&details.Details, "Contract", &address)
+			details.Addresses = append(details.Addresses, address)
+		}
+		appendPayload(details, wasmPayload(message.Msg))
+	case "/cosmwasm.wasm.v1.MsgMigrateContract":
+		appendAddressIfNotNil(&details.Details, "Contract", message.Contract)
+		appendIfNotNil(&details.Details, "New Code ID", message.CodeID)
+		appendPayload(details, wasmPayload(message.Msg))
+	case "/cosmwasm.wasm.v1.MsgStoreCode":
+		if codeID := findEventAttribute(d.apiData, msgIndex, "store_code", "code_id"); codeID != "" {
+			appendIfNotNil(&details.Details, "Code ID", &codeID)
+		}
+		if message.WasmByteCode != nil {
+			size := fmt.Sprintf("%d KiB", base64.StdEncoding.DecodedLen(len(*message.WasmByteCode))/1024)
+			appendIfNotNil(&details.Details, "Code Size", &size)
+		}
+	default:
+		return
+	}
+
+	if funds := d.prices.value(d.chain.displayCoins(message.Funds)); len(funds) > 0 {
+		text := formatCoins(funds)
+		appendIfNotNil(&details.Details, "Funds", &text)
+		details.Amounts = append(details.Amounts, funds...)
+	}
+	for _, address := range []*string{message.Contract, message.Admin} {
+		if address != nil && *address != "" {
+			details.Addresses = append(details.Addresses, *address)
+		}
+	}
+	events := findEvents(d.apiData, msgIndex, "wasm")
+	for i, event := range events {
+		if i == wasmEventsShown {
+			more := fmt.Sprintf("%d more", len(events)-wasmEventsShown)
+			appendIfNotNil(&details.Details, "Wasm Event", &more)
+			break
+		}
+		text := wasmEventText(event, stringValue(message.Contract))
+		appendIfNotNil(&details.Details, "Wasm Event", &text)
+	}
+}
+
+// wasmPayload returns the JSON message sent to a contract, which APIs serve either as
+// JSON or base64.
+func wasmPayload(msg json.RawMessage) json.RawMessage {
+	var encoded string
+	if json.Unmarshal(msg, &encoded) == nil {
+		if decoded, err := base64.StdEncoding.DecodeString(encoded); err == nil && json.Valid(decoded) {
+			return decoded
+		}
+	}
+	return msg
+}
+
+// wasmMethod returns the name of an execute message, its only key: "transfer" for
+// {"transfer": {...}}.
+func wasmMethod(payload json.RawMessage) string {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(payload, &fields) != nil || len(fields) != 1 {
+		return ""
+	}
+	for method := range fields {
+		return method
+	}
+	return ""
+}
+
+func appendPayload(details *MessageDetail, payload json.RawMessage) {
+	if len(payload) == 0 {
+		return
+	}
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, payload, "", "  "); err != nil {
+		return
+	}
+	text := truncate(pretty.String(), wasmPayloadMax)
+	appendIfNotNil(&details.Details, "Message", &text)
+}
+
+// wasmEventText renders the attributes of a wasm event as "action=transfer, amount=10",
+// prefixed by the contract when another contract than the one called emitted it.
+func wasmEventText(event Event, contract string) string {
+	var parts []string
+	emitter := ""
+	for _, attr := range event.Attributes {
+		switch attr.Key {
+		case "_contract_address":
+			emitter = attr.Value
+		case "msg_index":
+		default:
+			parts = append(parts, attr.Key+"="+attr.Value)
+		}
+	}
+	text := strings.Join(parts, ", ")
+	if emitter != "" && emitter != contract {
+		text = addressBook.Display(emitter) + ": " + text
+	}
+	return truncate(text, wasmEventMax)
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// decodeCW20 renders the execute messages of a CW20 token contract, such as
+// {"transfer": {"recipient": "osmo1…", "amount": "1000000"}}, with the amount in tokens.
+func decodeCW20(d messageDecoder, details *MessageDetail, contract ContractConfig, method string, payload json.RawMessage) bool {
+	var fields map[string]struct {
+		Recipient string `json:"recipient"`
+		Contract  string `json:"contract"`
+		Owner     string `json:"owner"`
+		Spender   string `json:"spender"`
+		Amount    string `json:"amount"`
+	}
+	if json.Unmarshal(payload, &fields) != nil {
+		return false
+	}
+	switch method {
+	case "transfer", "send", "burn", "mint", "transfer_from", "send_from", "burn_from",
+		"increase_allowance", "decrease_allowance":
+	default:
+		return false
+	}
+	params := fields[method]
+
+	words := strings.Split(method, "_")
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	details.Action = "CW20 " + strings.Join(words, " ")
+	for _, address := range []struct{ key, value string }{
+		{"Owner", params.Owner},
+		{"Recipient", params.Recipient},
+		{"Recipient Contract", params.Contract},
+		{"Spender", params.Spender},
+	} {
+		if address.value != "" {
+			appendAddressIfNotNil(&details.Details, address.key, &address.value)
+			details.Addresses = append(details.Addresses, address.value)
+		}
+	}
+	if value, err := ParseDecimal(params.Amount); err == nil {
+		symbol := contract.Symbol
+		if symbol == "" {
+			symbol = "tokens"
+		}
+		coins := d.prices.value([]Coin{{Denom: symbol, Amount: value.Shift(contract.Decimals)}})
+		amount := formatCoins(coins)
+		appendIfNotNil(&details.Details, "Amount", &amount)
+		details.Amounts = append(details.Amounts, coins...)
+	}
+	return true
+}
+
+// contractDecoderNames lists the decoders for config errors.
+func contractDecoderNames() string {
+	var names []string
+	for name := range contractDecoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sacOO7/gowebsocket"
@@ -60,29 +62,44 @@ type Attribute struct {
 	// Index bool   `json:"index"`
 }
 
-// , updateFunc func(chainName string, height string, timestamp string)
-func SubscribeToNewBlocks(cfg *Config, chain ChainConfig, chainName string, address string) {
+// SubscribeToNewBlocks watches a wallet's transactions, reconnecting until the returned
+// stop function is called.
+func SubscribeToNewBlocks(cfg *Config, chain ChainConfig, chainName string, address string) (stop func()) {
 	wsURL := TransformToWebSocketURL(chain.RPC)
 	socket := gowebsocket.New(wsURL)
 	log.Printf("Attempting to connect to WebSocket for chain: %s, address: %s", chainName, address)
+	connections.Set(chainName, address, false, nil)
 
-	reconnectFunc := func() {
-		time.Sleep(1 * time.Minute) // 5초 후 재연결 시도
-		socket.Connect()
+	var stopped atomic.Bool
+	queries := subscriptionQueries(cfg, address)
+	subscribe := func(socket gowebsocket.Socket) {
 		log.Println("supscribe to : ", address)
-		socket.SendText(fmt.Sprintf("{ \"jsonrpc\": \"2.0\", \"method\": \"subscribe\", \"params\": [\"transfer.sender ='%s'\"], \"id\": 1 }", address))
+		for i, query := range queries {
+			socket.SendText(fmt.Sprintf("{ \"jsonrpc\": \"2.0\", \"method\": \"subscribe\", \"params\": [\"%s\"], \"id\": %d }", query, i+1))
+		}
 
 		// socket.SendText("{ \"jsonrpc\": \"2.0\", \"method\": \"subscribe\", \"params\": [\"tm.event = 'Tx'\"], \"id\": 1 }")
+	}
 
+	reconnectFunc := func() {
+		time.Sleep(1 * time.Minute) // 5초 후 재연결 시도
+		if stopped.Load() {
+			return
+		}
+		socket.Connect()
+		subscribe(socket)
 	}
 	socket.OnConnected = func(socket gowebsocket.Socket) {
-		log.Println("supscribe to : ", address)
-		socket.SendText(fmt.Sprintf("{ \"jsonrpc\": \"2.0\", \"method\": \"subscribe\", \"params\": [\"transfer.sender ='%s'\"], \"id\": 1 }", address))
-
-		// socket.SendText("{ \"jsonrpc\": \"2.0\", \"method\": \"subscribe\", \"params\": [\"tm.event = 'Tx'\"], \"id\": 1 }")
-
+		if stopped.Load() {
+			socket.Close()
+			return
+		}
+		connections.Set(chainName, address, true, nil)
+		subscribe(socket)
 	}
 
+	// A transaction matching several queries is delivered once per subscription.
+	seen := newRecentSet(100)
 	socket.OnTextMessage = func(message string, socket gowebsocket.Socket) {
 		txhash, err := extractDataFromMessage(message)
 		// log.Println(message)
@@ -91,20 +108,118 @@ func SubscribeToNewBlocks(cfg *Config, chain ChainConfig, chainName string, addr
 			log.Printf("Error parsing message from WebSocket: %v", err)
 			return
 		}
-		if txhash != "" {
-			alertChan <- NewAlert(chainName, txhash)
+		if txhash != "" && !stopped.Load() && seen.Add(txhash) {
+			alertChan <- NewAlert(chainName, address, txhash)
 		}
 	}
 	socket.OnDisconnected = func(err error, socket gowebsocket.Socket) {
+		if stopped.Load() {
+			return
+		}
+		connections.Set(chainName, address, false, err)
 		log.Print(fmt.Sprintln("WebSocket disconnected: ", err, ". Reconnecting...", wsURL))
 		reconnectFunc()
 	}
 	socket.OnConnectError = func(err error, socket gowebsocket.Socket) {
+		if stopped.Load() {
+			return
+		}
+		connections.Set(chainName, address, false, err)
 		log.Print(fmt.Sprintln("Received connect error ", err, "\t : ", wsURL))
 		reconnectFunc()
 	}
 
-	socket.Connect()
+	go socket.Connect()
+	return func() {
+		if stopped.Swap(true) {
+			return
+		}
+		log.Printf("Unsubscribing from chain: %s, address: %s", chainName, address)
+		if socket.IsConnected {
+			socket.Close()
+		}
+		connections.Remove(chainName, address)
+	}
+}
+
+// subscriptionQueries returns the Tendermint event queries of a wallet. When Slack
+// follow-ups are enabled it also watches acknowledgements and timeouts of the
+// wallet's IBC transfers, which are signed by relayers rather than the wallet; see
+// AlertData.followUpOnly.
+func subscriptionQueries(cfg *Config, address string) []string {
+	queries := []string{fmt.Sprintf("transfer.sender ='%s'", address)}
+	if cfg.Alerting.Slack.Bot.Enable {
+		queries = append(queries,
+			fmt.Sprintf("fungible_token_packet.sender ='%s'", address),
+			fmt.Sprintf("timeout.refund_receiver ='%s'", address),
+		)
+	}
+	return queries
+}
+
+// transferSenders collects the senders of a tx's transfer events, which the wallet's
+// own query matches on.
+func transferSenders(apiData *Response) map[string]bool {
+	senders := make(map[string]bool)
+	events := append([]Event(nil), apiData.TxResponse.Events...)
+	for _, log := range apiData.TxResponse.Logs {
+		events = append(events, log.Events...)
+	}
+	for _, event := range events {
+		if event.Type != "transfer" {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if attr.Key == "sender" {
+				senders[attr.Value] = true
+			}
+		}
+	}
+	return senders
+}
+
+// followUpOnly reports whether the tx reached the wallet only through the follow-up
+// queries: it settles IBC packets and the wallet sent nothing in it. Such relayer txs
+// are for notifiers following up on earlier transfers, not alerts of their own.
+func (a AlertData) followUpOnly() bool {
+	if a.transferSenders[a.WalletAddress] {
+		return false
+	}
+	for _, packet := range a.IBCPackets {
+		if packet.Kind == IBCPacketAcknowledgement || packet.Kind == IBCPacketTimeout {
+			return true
+		}
+	}
+	return false
+}
+
+// recentSet remembers the last size values added to it.
+type recentSet struct {
+	mu     sync.Mutex
+	size   int
+	order  []string
+	values map[string]bool
+}
+
+func newRecentSet(size int) *recentSet {
+	return &recentSet{size: size, values: make(map[string]bool)}
+}
+
+// Add returns false if value was already seen recently.
+func (r *recentSet) Add(value string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.values[value] {
+		return false
+	}
+	r.values[value] = true
+	r.order = append(r.order, value)
+	if len(r.order) > r.size {
+		delete(r.values, r.order[0])
+		r.order = r.order[1:]
+	}
+	return true
 }
 
 func extractDataFromMessage(message string) (string, error) {